@@ -0,0 +1,235 @@
+/*
+Copyright © 2026 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/patch"
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	applyWrite bool
+	applyYes   bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply [<pr-number> | <pr-url> | <branch>]",
+	Short: "Apply suggested changes from unresolved review threads to the local checkout",
+	Long: `apply collects suggestion blocks from review threads that are not resolved on GitHub and converts them into a unified diff against the local checkout.
+
+By default the patch is printed to stdout. With --write, each suggestion is confirmed interactively and applied to the working tree.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		setupLogger()
+
+		if applyWrite && !applyYes && !term.IsTerminal(int(os.Stdin.Fd())) { //nolint:gosec // Fd() returns a small file descriptor.
+			return errors.New("--write needs an interactive terminal to confirm each suggestion; use --yes to apply without confirmation")
+		}
+
+		s := newSpinner()
+		_, _, data, err := fetchReviewData(ctx, s, args)
+		s.Stop()
+		if err != nil {
+			return err
+		}
+
+		root, err := gitOutput("rev-parse", "--show-toplevel")
+		if err != nil {
+			return err
+		}
+		if head, err := gitOutput("rev-parse", "HEAD"); err == nil && data.HeadCommitID != "" && head != data.HeadCommitID {
+			fmt.Fprintf(os.Stderr, "warning: local HEAD %s differs from the PR head %s\n", shortSHA(head), shortSHA(data.HeadCommitID))
+		}
+
+		suggestions := review.PendingSuggestions(data)
+		if len(suggestions) == 0 {
+			fmt.Fprintln(os.Stderr, "No unresolved suggestions found.")
+			return nil
+		}
+
+		type fileEdits struct {
+			path    string
+			content string
+			edits   []patch.Edit
+		}
+		var files []*fileEdits
+		fileIdx := map[string]*fileEdits{}
+		stdin := bufio.NewReader(os.Stdin)
+
+	loop:
+		for _, sg := range suggestions {
+			f, ok := fileIdx[sg.Path]
+			if !ok {
+				b, err := readRepoFile(root, sg.Path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "skip %s: %v\n", sg.URL, err)
+					continue
+				}
+				f = &fileEdits{path: sg.Path, content: string(b)}
+				fileIdx[sg.Path] = f
+				files = append(files, f)
+			}
+
+			switch {
+			case sg.IsAppliedTo(f.content):
+				fmt.Fprintf(os.Stderr, "skip %s: already applied\n", sg.URL)
+				continue
+			case !sg.MatchesOriginal(f.content):
+				fmt.Fprintf(os.Stderr, "skip %s: %s:%s no longer matches the commented code\n", sg.URL, sg.Path, lineLabel(sg.StartLine, sg.EndLine))
+				continue
+			}
+
+			edit := patch.Edit{StartLine: sg.StartLine, EndLine: sg.EndLine, Lines: sg.Lines}
+			if _, err := patch.Apply(f.content, append(slices.Clone(f.edits), edit)); err != nil {
+				fmt.Fprintf(os.Stderr, "skip %s: %v\n", sg.URL, err)
+				continue
+			}
+
+			if applyWrite && !applyYes {
+				diff, err := patch.Unified(sg.Path, f.content, []patch.Edit{edit})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "\nSuggestion by @%s on %s:%s\n%s\n%s", sg.Author, sg.Path, lineLabel(sg.StartLine, sg.EndLine), sg.URL, diff)
+				answer, err := prompt(stdin, "Apply this suggestion? [y/N/q] ")
+				if err != nil {
+					return err
+				}
+				switch answer {
+				case "y", "yes":
+				case "q", "quit":
+					break loop
+				default:
+					continue
+				}
+			}
+			f.edits = append(f.edits, edit)
+		}
+
+		for _, f := range files {
+			if len(f.edits) == 0 {
+				continue
+			}
+			if !applyWrite {
+				diff, err := patch.Unified(f.path, f.content, f.edits)
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(os.Stdout, diff)
+				continue
+			}
+			updated, err := patch.Apply(f.content, f.edits)
+			if err != nil {
+				return err
+			}
+			if err := writeRepoFile(root, f.path, []byte(updated)); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Applied %d suggestion(s) to %s\n", len(f.edits), f.path)
+		}
+
+		return nil
+	},
+}
+
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// repoPath returns the local path of a repository-relative path, refusing paths outside root.
+func repoPath(root, path string) (string, error) {
+	p := filepath.Join(root, filepath.FromSlash(path))
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the repository", path)
+	}
+	return p, nil
+}
+
+func readRepoFile(root, path string) ([]byte, error) {
+	p, err := repoPath(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p) //nolint:gosec // p is confined to the repository root.
+}
+
+func writeRepoFile(root, path string, b []byte) error {
+	p, err := repoPath(root, path)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, fi.Mode().Perm())
+}
+
+func prompt(r *bufio.Reader, msg string) (string, error) {
+	fmt.Fprint(os.Stderr, msg)
+	answer, err := r.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.ToLower(strings.TrimSpace(answer)), nil
+}
+
+func lineLabel(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("L%d", end)
+	}
+	return fmt.Sprintf("L%d-L%d", start, end)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func init() {
+	applyCmd.Flags().BoolVar(&applyWrite, "write", false, "Apply the suggestions to the working tree instead of printing the patch")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Apply without confirming each suggestion (with --write)")
+	rootCmd.AddCommand(applyCmd)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright © 2026 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/gh"
+	"github.com/k1LoW/gh-pr-reviews/output"
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/spf13/cobra"
+)
+
+// stickyMarker identifies the comment maintained by the ci subcommand.
+const stickyMarker = "<!-- gh-pr-reviews -->"
+
+var ciNoComment bool
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Report unresolved review comments from GitHub Actions",
+	Long: `ci runs the analysis for the pull request of the current GitHub Actions event and reports the unresolved review comments as a checklist.
+
+The checklist is kept in a single comment on the pull request, which is created on the first run and updated afterwards, and is also written to the job summary.`,
+	Example: `  $ gh pr-reviews ci
+  $ gh pr-reviews ci --exit-status --fail-on issue,question`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := cmd.Context()
+		setupLogger()
+
+		thresholds, err := parseFailOn()
+		if err != nil {
+			return err
+		}
+
+		number, err := eventPRNumber(os.Getenv("GITHUB_EVENT_PATH"))
+		if err != nil {
+			return err
+		}
+		repoSelector := flagRepoSelector
+		if repoSelector == "" {
+			repoSelector = os.Getenv("GITHUB_REPOSITORY")
+		}
+		if repoSelector == "" {
+			return errors.New("GITHUB_REPOSITORY is not set; use --repo")
+		}
+		owner, repo, err := resolveRepo(repoSelector)
+		if err != nil {
+			return err
+		}
+		prInfo := &prContext{owner: owner, repo: repo, number: number}
+		slog.Info("resolved PR", "owner", owner, "repo", repo, "number", number)
+
+		ghClient, err := gh.New()
+		if err != nil {
+			return err
+		}
+		data, err := ghClient.FetchReviews(ctx, owner, repo, number)
+		if err != nil {
+			return err
+		}
+		data.PRComments = excludeSticky(data.PRComments)
+		slog.Info("fetched review data", "threads", len(data.Threads), "pr_comments", len(data.PRComments))
+
+		s := newSpinner()
+		if err := fetchFiles(ctx, s, ghClient, prInfo, data, review.SuggestionPaths(data)); err != nil {
+			return err
+		}
+
+		classifier, err := review.NewCopilotClassifier(ctx, copilotModel)
+		if err != nil {
+			return fmt.Errorf("failed to create classifier: %w", err)
+		}
+		defer classifier.Close()
+
+		results, err := review.Analyze(ctx, data, classifier, false)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		output.RenderChecklist(&buf, results)
+		report := buf.String()
+
+		if !ciNoComment {
+			url, err := ghClient.UpsertIssueComment(ctx, owner, repo, number, stickyMarker, stickyMarker+"\n"+report)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Updated %s\n", url)
+		}
+		if err := writeStepSummary(os.Getenv("GITHUB_STEP_SUMMARY"), report); err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, report)
+
+		return checkExitStatus(results, thresholds)
+	},
+}
+
+// eventPRNumber returns the pull request number of the GitHub Actions event at path.
+func eventPRNumber(path string) (int, error) {
+	if path == "" {
+		return 0, errors.New("GITHUB_EVENT_PATH is not set; ci must run in GitHub Actions")
+	}
+	b, err := os.ReadFile(path) //nolint:gosec // The path is given by GitHub Actions.
+	if err != nil {
+		return 0, fmt.Errorf("failed to read event: %w", err)
+	}
+	var event struct {
+		PullRequest *struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+		Issue *struct {
+			Number      int             `json:"number"`
+			PullRequest json.RawMessage `json:"pull_request"`
+		} `json:"issue"`
+	}
+	if err := json.Unmarshal(b, &event); err != nil {
+		return 0, fmt.Errorf("failed to parse event: %w", err)
+	}
+	switch {
+	case event.PullRequest != nil && event.PullRequest.Number > 0:
+		return event.PullRequest.Number, nil
+	case event.Issue != nil && event.Issue.PullRequest != nil && event.Issue.Number > 0:
+		// issue_comment events on pull requests.
+		return event.Issue.Number, nil
+	}
+	return 0, errors.New("the event is not for a pull request")
+}
+
+// excludeSticky drops the comment maintained by the ci subcommand, which
+// starts with stickyMarker. Replies quoting it are kept.
+func excludeSticky(comments []review.Comment) []review.Comment {
+	var kept []review.Comment
+	for _, c := range comments {
+		if !strings.HasPrefix(c.Body, stickyMarker) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// writeStepSummary appends report to the job summary file at path, if any.
+func writeStepSummary(path, report string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // The path is given by GitHub Actions.
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	if _, err := f.WriteString(report); err != nil {
+		f.Close() //nolint:errcheck
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	return f.Close()
+}
+
+func init() {
+	ciCmd.Flags().BoolVar(&ciNoComment, "no-comment", false, "Only write the job summary without commenting on the pull request")
+	addExitStatusFlags(ciCmd)
+	addCopilotModelFlag(ciCmd)
+	rootCmd.AddCommand(ciCmd)
+}
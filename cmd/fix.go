@@ -0,0 +1,210 @@
+/*
+Copyright © 2026 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/k1LoW/gh-pr-reviews/patch"
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	fixWrite bool
+	fixYes   bool
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix [<pr-number> | <pr-url> | <branch>]",
+	Short: "Propose patches for unresolved review feedback",
+	Long: `fix asks Copilot for a minimal patch for each unresolved suggestion, issue and nitpick thread, using the conversation, the diff hunk and the file in the local checkout.
+
+Each patch is proposed for and checked against the local checkout with the earlier patches applied, and skipped if it does not apply cleanly. By default the patches are printed to stdout. With --write, each patch is confirmed interactively and applied to the working tree.`,
+	Example: `  $ gh pr-reviews fix 123 > fixes.patch
+  $ gh pr-reviews fix 123 --write`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		setupLogger()
+
+		if fixWrite && !fixYes && !term.IsTerminal(int(os.Stdin.Fd())) { //nolint:gosec // Fd() returns a small file descriptor.
+			return errors.New("--write needs an interactive terminal to confirm each patch; use --yes to apply without confirmation")
+		}
+
+		s := newSpinner()
+		_, _, data, err := fetchReviewData(ctx, s, args)
+		if err != nil {
+			s.Stop()
+			return err
+		}
+
+		root, err := gitOutput("rev-parse", "--show-toplevel")
+		if err != nil {
+			s.Stop()
+			return err
+		}
+		if head, err := gitOutput("rev-parse", "HEAD"); err == nil && data.HeadCommitID != "" && head != data.HeadCommitID {
+			fmt.Fprintf(os.Stderr, "warning: local HEAD %s differs from the PR head %s\n", shortSHA(head), shortSHA(data.HeadCommitID))
+		}
+
+		s.Suffix = " Starting Copilot..."
+		classifier, err := review.NewCopilotClassifier(ctx, copilotModel)
+		if err != nil {
+			s.Stop()
+			return fmt.Errorf("failed to create classifier: %w", err)
+		}
+		defer classifier.Close()
+
+		s.Suffix = " Classifying review comments..."
+		results, err := review.Analyze(ctx, data, classifier, false)
+		s.Stop()
+		if err != nil {
+			return err
+		}
+
+		targets := review.FixTargets(results)
+		if len(targets) == 0 {
+			fmt.Fprintln(os.Stderr, "No unresolved suggestions or issues found.")
+			return nil
+		}
+
+		// In print mode, fixes are applied to a scratch copy of the files they
+		// change, so that each fix is proposed for and checked against the files
+		// with the earlier printed fixes applied, as when the output is applied
+		// as a whole.
+		tree := root
+		if !fixWrite {
+			scratch, err := os.MkdirTemp("", "gh-pr-reviews-fix-*")
+			if err != nil {
+				return fmt.Errorf("failed to create temporary directory: %w", err)
+			}
+			defer os.RemoveAll(scratch) //nolint:errcheck
+			tree = scratch
+		}
+
+		threads := map[string]review.Thread{}
+		for _, t := range data.Threads {
+			threads[t.ID] = t
+		}
+		stdin := bufio.NewReader(os.Stdin)
+
+		for _, r := range targets {
+			if tree != root {
+				if err := copyRepoFile(root, tree, r.Path); err != nil {
+					fmt.Fprintf(os.Stderr, "skip %s: %v\n", r.URL, err)
+					continue
+				}
+			}
+			b, err := readRepoFile(tree, r.Path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "skip %s: %v\n", r.URL, err)
+				continue
+			}
+
+			s.Suffix = fmt.Sprintf(" Proposing a fix for %s...", r.Path)
+			s.Start()
+			diff, err := classifier.ProposeFix(ctx, review.NewFixInput(threads[r.ThreadID], string(b)))
+			s.Stop()
+			if err != nil {
+				return err
+			}
+
+			if files := patch.Files(diff); !slices.Equal(files, []string{r.Path}) {
+				fmt.Fprintf(os.Stderr, "skip %s: proposed patch does not change only %s\n", r.URL, r.Path)
+				continue
+			}
+			if err := patch.Check(tree, diff); err != nil {
+				fmt.Fprintf(os.Stderr, "skip %s: %v\n", r.URL, err)
+				continue
+			}
+
+			if !fixWrite {
+				if err := patch.ApplyToTree(tree, diff); err != nil {
+					fmt.Fprintf(os.Stderr, "skip %s: %v\n", r.URL, err)
+					continue
+				}
+				fmt.Fprintf(os.Stderr, "Fix for %s by @%s: %s\n", r.Category, r.Author, r.URL)
+				fmt.Fprintln(os.Stdout, diff)
+				continue
+			}
+
+			if !fixYes {
+				fmt.Fprintf(os.Stderr, "\n%s by @%s on %s\n%s\n\n%s\n\n%s\n", r.Category, r.Author, r.Path, r.URL, r.Body, diff)
+				answer, err := prompt(stdin, "Apply this patch? [y/N/q] ")
+				if err != nil {
+					return err
+				}
+				switch answer {
+				case "y", "yes":
+				case "q", "quit":
+					return nil
+				default:
+					continue
+				}
+			}
+			if err := patch.ApplyToTree(root, diff); err != nil {
+				fmt.Fprintf(os.Stderr, "skip %s: %v\n", r.URL, err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Applied a fix to %s for %s\n", r.Path, r.URL)
+		}
+
+		return nil
+	},
+}
+
+// copyRepoFile copies path from the repository at root to the same path under
+// dst, unless it is already there.
+func copyRepoFile(root, dst, path string) error {
+	p, err := repoPath(dst, path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(p); err == nil {
+		return nil
+	}
+	b, err := readRepoFile(root, path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	fixCmd.Flags().BoolVar(&fixWrite, "write", false, "Apply the patches to the working tree instead of printing them")
+	fixCmd.Flags().BoolVarP(&fixYes, "yes", "y", false, "Apply without confirming each patch (with --write)")
+	fixCmd.Flags().StringVar(&copilotModel, "copilot-model", "claude-haiku-4.5", "Copilot model to use")
+	rootCmd.AddCommand(fixCmd)
+}
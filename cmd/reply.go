@@ -0,0 +1,177 @@
+/*
+Copyright © 2026 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/gh"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replyBody     string
+	replyBodyFile string
+	replyResolve  bool
+)
+
+var replyCmd = &cobra.Command{
+	Use:   "reply <thread-id | comment-id>",
+	Short: "Reply to a review thread or a PR comment",
+	Long: `reply posts a reply to a review thread (given by thread_id), or to a comment given by comment_id: a quote reply to a PR comment, or a reply in the thread of a review comment.
+
+The body is taken from --body, --body-file, or an editor ($GH_EDITOR, $VISUAL or $EDITOR).`,
+	Example: `  $ gh pr-reviews reply PRRT_kwDOH7hXo85vAD-t --body "Fixed in abc1234" --resolve
+  $ gh pr-reviews reply 2815800000 --body-file answer.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		setupLogger()
+
+		target := args[0]
+		commentID, err := strconv.ParseInt(target, 10, 64)
+		isComment := err == nil
+		if isComment && replyResolve {
+			return errors.New("--resolve can only be used with a thread ID")
+		}
+
+		var owner, repo string
+		if isComment {
+			owner, repo, err = resolveRepo(flagRepoSelector)
+			if err != nil {
+				return err
+			}
+		}
+
+		body, err := readReplyBody(replyBody, replyBodyFile)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(body) == "" {
+			return errors.New("aborted: the reply body is empty")
+		}
+
+		ghClient, err := gh.New()
+		if err != nil {
+			return err
+		}
+
+		if isComment {
+			url, err := ghClient.ReplyToComment(ctx, owner, repo, commentID, body)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, url)
+			return nil
+		}
+
+		url, err := ghClient.ReplyToThread(ctx, target, body)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, url)
+
+		if replyResolve {
+			if err := ghClient.ResolveThread(ctx, target); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "✓ Resolved %s\n", target)
+		}
+		return nil
+	},
+}
+
+// readReplyBody returns the reply body from the flag, the file ("-" for stdin), or an editor.
+func readReplyBody(body, bodyFile string) (string, error) {
+	switch {
+	case body != "" && bodyFile != "":
+		return "", errors.New("--body and --body-file cannot be combined")
+	case body != "":
+		return body, nil
+	case bodyFile == "-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body from stdin: %w", err)
+		}
+		return string(b), nil
+	case bodyFile != "":
+		b, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body file: %w", err)
+		}
+		return string(b), nil
+	default:
+		return editBody("")
+	}
+}
+
+// editBody opens initial in the user's editor and returns the edited text.
+func editBody(initial string) (string, error) {
+	editor := "vi"
+	for _, env := range []string{"GH_EDITOR", "VISUAL", "EDITOR"} {
+		if v := os.Getenv(env); v != "" {
+			editor = v
+			break
+		}
+	}
+
+	f, err := os.CreateTemp("", "gh-pr-reviews-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close() //nolint:errcheck
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], f.Name())...) //nolint:gosec // The editor is chosen by the user.
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temporary file: %w", err)
+	}
+	return string(b), nil
+}
+
+func init() {
+	replyCmd.Flags().StringVarP(&replyBody, "body", "b", "", "Reply body text")
+	replyCmd.Flags().StringVarP(&replyBodyFile, "body-file", "F", "", `Read the reply body from file (use "-" to read from standard input)`)
+	replyCmd.Flags().BoolVar(&replyResolve, "resolve", false, "Resolve the thread after replying")
+	rootCmd.AddCommand(replyCmd)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2026 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/gh-pr-reviews/gh"
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resolvePRSelector string
+	resolveFilter     review.ThreadFilter
+	resolveUnresolve  bool
+	resolveDryRun     bool
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve [<thread-id>...]",
+	Short: "Resolve review threads",
+	Long: `resolve marks review threads as resolved (or unresolved with --unresolve).
+
+Threads are given by their IDs (as emitted in thread_id), or selected from a pull request with filters such as --author, --path and --outdated.`,
+	Example: `  $ gh pr-reviews resolve PRRT_kwDOH7hXo85vAD-t
+  $ gh pr-reviews resolve --pr 123 --outdated --dry-run
+  $ gh pr-reviews resolve --author alice --path 'docs/*'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		setupLogger()
+
+		if len(args) > 0 && (!resolveFilter.IsZero() || resolvePRSelector != "") {
+			return errors.New("thread IDs cannot be combined with --pr or filters")
+		}
+		if len(args) == 0 && resolveFilter.IsZero() {
+			return errors.New("specify thread IDs or at least one of --author, --path, --outdated")
+		}
+
+		type target struct {
+			id    string
+			label string
+		}
+		var targets []target
+		var ghClient *gh.Client
+
+		if len(args) > 0 {
+			for _, id := range args {
+				targets = append(targets, target{id: id, label: id})
+			}
+		} else {
+			var prArgs []string
+			if resolvePRSelector != "" {
+				prArgs = []string{resolvePRSelector}
+			}
+			s := newSpinner()
+			_, c, data, err := fetchReviewData(ctx, s, prArgs)
+			s.Stop()
+			if err != nil {
+				return err
+			}
+			ghClient = c
+			for _, t := range data.Threads {
+				// Skip threads that are already in the requested state.
+				if t.IsResolved != resolveUnresolve || !resolveFilter.Match(t) {
+					continue
+				}
+				targets = append(targets, target{id: t.ID, label: fmt.Sprintf("%s (%s)", t.ID, t.Path)})
+			}
+		}
+
+		verb, past := "resolve", "Resolved"
+		if resolveUnresolve {
+			verb, past = "unresolve", "Unresolved"
+		}
+
+		if len(targets) == 0 {
+			fmt.Fprintf(os.Stderr, "No threads to %s.\n", verb)
+			return nil
+		}
+
+		if resolveDryRun {
+			for _, t := range targets {
+				fmt.Fprintf(os.Stdout, "Would %s %s\n", verb, t.label)
+			}
+			return nil
+		}
+
+		if ghClient == nil {
+			c, err := gh.New()
+			if err != nil {
+				return err
+			}
+			ghClient = c
+		}
+
+		failed := 0
+		for _, t := range targets {
+			var err error
+			if resolveUnresolve {
+				err = ghClient.UnresolveThread(ctx, t.id)
+			} else {
+				err = ghClient.ResolveThread(ctx, t.id)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "✓ %s %s\n", past, t.label)
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to %s %d of %d thread(s)", verb, failed, len(targets))
+		}
+		return nil
+	},
+}
+
+func init() {
+	resolveCmd.Flags().StringVar(&resolvePRSelector, "pr", "", "Pull request to select threads from with filters (number, URL or branch; default: current branch)")
+	resolveCmd.Flags().StringVar(&resolveFilter.Author, "author", "", "Select threads started by this user")
+	resolveCmd.Flags().StringVar(&resolveFilter.Path, "path", "", "Select threads on files matching this glob pattern")
+	resolveCmd.Flags().BoolVar(&resolveFilter.Outdated, "outdated", false, "Select threads whose diff is outdated")
+	resolveCmd.Flags().BoolVar(&resolveUnresolve, "unresolve", false, "Unresolve the threads instead")
+	resolveCmd.Flags().BoolVar(&resolveDryRun, "dry-run", false, "Show the threads that would be changed without changing them")
+	rootCmd.AddCommand(resolveCmd)
+}
@@ -22,12 +22,14 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -47,10 +49,43 @@ var (
 	showAll          bool
 	copilotModel     string
 	verbose          bool
-	jsonOutput       bool
+	jsonFields       []string
+	outputFormat     string
+	templateFlag     string
+	jqFlag           string
+	jsonEnvelope     bool
+	showStats        bool
+	groupBy          string
+	sortBy           string
+	batchSize        int
 	widthFlag        int
+	showThread       bool
+	lastReplies      int
+	explain          bool
+	showDiff         bool
+	diffLines        int
+	autoResolve      bool
+	minConfidence    float64
+	autoResolveReply bool
+	dryRun           bool
+	draftReplies     bool
+	exitStatus       bool
+	failOn           []string
 )
 
+// exitCodeUnresolved is the exit code of --exit-status when unresolved
+// comments remain, matching gh pr checks for pending checks.
+const exitCodeUnresolved = 8
+
+// exitError makes Execute exit with code without printing a message.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
 var rootCmd = &cobra.Command{
 	Use:     "gh-pr-reviews [<pr-number> | <pr-url> | <branch>]",
 	Short:   "Show unresolved review comments for a pull request",
@@ -58,41 +93,89 @@ var rootCmd = &cobra.Command{
 	Version: version.Version,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
+		setupLogger()
 
-		level := slog.LevelError
-		if verbose {
-			level = slog.LevelInfo
+		if (dryRun || autoResolveReply) && !autoResolve {
+			return errors.New("--dry-run and --auto-resolve-reply require --auto-resolve")
 		}
-		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
-
-		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond, spinner.WithWriter(colorable.NewColorableStderr()))
-		_ = s.Color("fgHiMagenta")
-
-		// Resolve PR context via gh CLI.
-		s.Suffix = " Resolving PR..."
-		s.Start()
-		prInfo, err := resolvePR(args, flagRepoSelector)
+		if cmd.Flags().Changed("json") {
+			if slices.Equal(jsonFields, []string{allFields}) {
+				jsonFields = nil
+			}
+			if err := review.ValidateFields(jsonFields); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("format") && outputFormat != formatJSON && outputFormat != formatNDJSON {
+				return fmt.Errorf("--json cannot be combined with --format %s", outputFormat)
+			}
+			if outputFormat != formatNDJSON {
+				outputFormat = formatJSON
+			}
+		}
+		if cmd.Flags().Changed("last-replies") && !showThread {
+			return errors.New("--last-replies requires --thread")
+		}
+		if lastReplies < 0 {
+			return fmt.Errorf("--last-replies must not be negative, got %d", lastReplies)
+		}
+		if cmd.Flags().Changed("diff-lines") && !showDiff {
+			return errors.New("--diff-lines requires --diff")
+		}
+		if diffLines < 1 {
+			return fmt.Errorf("--diff-lines must be positive, got %d", diffLines)
+		}
+		if batchSize < 0 {
+			return fmt.Errorf("--batch-size must not be negative, got %d", batchSize)
+		}
+		if (templateFlag != "" || jqFlag != "") && outputFormat != formatMarkdown && outputFormat != formatJSON {
+			return fmt.Errorf("--template and --jq cannot be combined with --format %s", outputFormat)
+		}
+		if jsonEnvelope && outputFormat != formatJSON && templateFlag == "" && jqFlag == "" {
+			return errors.New("--json-envelope requires --json, --template or --jq")
+		}
+		if showStats && outputFormat != formatJSON {
+			return errors.New("--stats requires --json")
+		}
+		if showStats && (len(jsonFields) > 0 || jsonEnvelope) {
+			return errors.New("--stats cannot be combined with JSON fields or --json-envelope")
+		}
+		if groupBy != "" && !slices.Contains(output.GroupKeys, groupBy) {
+			return fmt.Errorf("invalid --group-by %q: must be one of %s", groupBy, strings.Join(output.GroupKeys, ", "))
+		}
+		if sortBy != "" && !slices.Contains(output.SortKeys, sortBy) {
+			return fmt.Errorf("invalid --sort %q: must be one of %s", sortBy, strings.Join(output.SortKeys, ", "))
+		}
+		if (groupBy != "" || sortBy != "") && (outputFormat != formatMarkdown || templateFlag != "" || jqFlag != "") {
+			return errors.New("--group-by and --sort require the markdown format")
+		}
+		if !slices.Contains(outputFormats, outputFormat) {
+			return fmt.Errorf("invalid --format %q: must be one of %s", outputFormat, strings.Join(outputFormats, ", "))
+		}
+		if minConfidence < 0 || minConfidence > 1 {
+			return fmt.Errorf("--min-confidence must be between 0.0 and 1.0, got %g", minConfidence)
+		}
+		thresholds, err := parseFailOn()
 		if err != nil {
-			s.Stop()
 			return err
 		}
-		slog.Info("resolved PR", "owner", prInfo.owner, "repo", prInfo.repo, "number", prInfo.number)
 
-		// Create GitHub GraphQL client.
-		ghClient, err := gh.New()
+		s := newSpinner()
+		prInfo, ghClient, data, err := fetchReviewData(ctx, s, args)
 		if err != nil {
 			s.Stop()
 			return err
 		}
 
-		// Fetch review data.
-		s.Suffix = " Fetching review data..."
-		data, err := ghClient.FetchReviews(ctx, prInfo.owner, prInfo.repo, prInfo.number)
-		if err != nil {
+		// Fetch head file contents to detect applied suggestions, and as
+		// context for drafting replies.
+		paths := review.SuggestionPaths(data)
+		if draftReplies {
+			paths = review.UnresolvedPaths(data)
+		}
+		if err := fetchFiles(ctx, s, ghClient, prInfo, data, paths); err != nil {
 			s.Stop()
 			return err
 		}
-		slog.Info("fetched review data", "threads", len(data.Threads), "pr_comments", len(data.PRComments))
 
 		// Create Copilot classifier.
 		s.Suffix = " Starting Copilot..."
@@ -105,28 +188,337 @@ var rootCmd = &cobra.Command{
 
 		// Analyze reviews.
 		s.Suffix = " Classifying review comments..."
-		results, err := review.Analyze(ctx, data, classifier, showAll)
+		var analyzeOpts []review.Option
+		if explain {
+			analyzeOpts = append(analyzeOpts, review.WithExplain())
+		}
+		if draftReplies {
+			analyzeOpts = append(analyzeOpts, review.WithReplyDrafter(classifier))
+		}
+		if batchSize > 0 {
+			analyzeOpts = append(analyzeOpts, review.WithBatchSize(batchSize))
+		}
+		// Auto-resolve and the stats need the resolved results too. So does the
+		// Markdown output, whose summary counts the resolved items; they are
+		// dropped again before rendering unless --all is given.
+		showResolved := showAll || autoResolve || jsonEnvelope || showStats || outputFormat == formatMarkdown
+
+		if outputFormat == formatNDJSON {
+			s.Stop()
+			return streamResults(ctx, data, classifier, ghClient, showResolved, analyzeOpts, thresholds)
+		}
+
+		results, err := review.Analyze(ctx, data, classifier, showResolved, analyzeOpts...)
 		s.Stop()
 		if err != nil {
 			return err
 		}
 
-		if jsonOutput {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			if err := enc.Encode(results); err != nil {
-				return fmt.Errorf("failed to encode output: %w", err)
+		if autoResolve {
+			if err := autoResolveThreads(ctx, ghClient, results); err != nil {
+				return err
 			}
-		} else {
-			p := termenv.NewOutput(os.Stdout, termenv.WithColorCache(true))
-			w := output.DetectWidth(widthFlag)
-			output.RenderMarkdown(os.Stdout, results, p, w)
 		}
 
-		return nil
+		stats := review.NewStats(results)
+		var envelope *review.Envelope
+		if jsonEnvelope {
+			envelope = &review.Envelope{
+				SchemaVersion: review.EnvelopeVersion,
+				PR: review.PRInfo{
+					Owner:   prInfo.owner,
+					Repo:    prInfo.repo,
+					Number:  prInfo.number,
+					Title:   data.Title,
+					HeadSHA: data.HeadCommitID,
+				},
+				GeneratedAt: time.Now().UTC(),
+				ToolVersion: version.Version,
+				Classifier:  review.ClassifierInfo{Backend: "copilot", Model: copilotModel},
+				Stats:       stats,
+			}
+		}
+		if !showAll {
+			results = review.Unresolved(results)
+		}
+
+		title := strings.TrimSpace(fmt.Sprintf("%s/%s#%d %s", prInfo.owner, prInfo.repo, prInfo.number, data.Title))
+		if err := renderResults(results, stats, envelope, title); err != nil {
+			return err
+		}
+
+		return checkExitStatus(results, thresholds)
 	},
 }
 
+// parseFailOn returns the thresholds of --fail-on.
+func parseFailOn() (review.Thresholds, error) {
+	if len(failOn) > 0 && !exitStatus {
+		return nil, errors.New("--fail-on requires --exit-status")
+	}
+	thresholds, err := review.ParseThresholds(failOn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	return thresholds, nil
+}
+
+// checkExitStatus returns an exitError with --exit-status if results exceed thresholds.
+func checkExitStatus(results []review.UnresolvedComment, thresholds review.Thresholds) error {
+	if !exitStatus {
+		return nil
+	}
+	if exceeded := thresholds.Exceeded(results); len(exceeded) > 0 {
+		fmt.Fprintf(os.Stderr, "Unresolved review comments: %s\n", strings.Join(exceeded, ", "))
+		return &exitError{code: exitCodeUnresolved}
+	}
+	return nil
+}
+
+// defaultStreamBatchSize is the batch size of --format ndjson without --batch-size.
+const defaultStreamBatchSize = 20
+
+// streamResults analyzes data in batches and writes the results of each batch
+// as NDJSON as soon as it is classified.
+func streamResults(ctx context.Context, data *review.Data, classifier *review.CopilotClassifier, ghClient *gh.Client, showResolved bool, opts []review.Option, thresholds review.Thresholds) error {
+	if batchSize == 0 {
+		opts = append(opts, review.WithBatchSize(defaultStreamBatchSize))
+	}
+	// Only the category and status are kept for --exit-status.
+	var tally []review.UnresolvedComment
+	opts = append(opts, review.WithHandler(func(batch []review.UnresolvedComment) error {
+		if autoResolve {
+			if err := autoResolveThreads(ctx, ghClient, batch); err != nil {
+				return err
+			}
+		}
+		if !showAll {
+			batch = review.Unresolved(batch)
+		}
+		if len(jsonFields) > 0 {
+			selected, err := review.SelectFields(batch, jsonFields)
+			if err != nil {
+				return err
+			}
+			if err := output.RenderNDJSON(os.Stdout, selected); err != nil {
+				return err
+			}
+		} else if err := output.RenderNDJSON(os.Stdout, batch); err != nil {
+			return err
+		}
+		for _, r := range batch {
+			tally = append(tally, review.UnresolvedComment{Category: r.Category, Resolved: r.Resolved})
+		}
+		return nil
+	}))
+	if _, err := review.Analyze(ctx, data, classifier, showResolved, opts...); err != nil {
+		return err
+	}
+	return checkExitStatus(tally, thresholds)
+}
+
+const (
+	formatMarkdown          = "markdown"
+	formatJSON              = "json"
+	formatGitHubAnnotations = "github-annotations"
+	formatSARIF             = "sarif"
+	formatJUnit             = "junit"
+	formatNDJSON            = "ndjson"
+	formatCSV               = "csv"
+	formatTSV               = "tsv"
+	formatHTML              = "html"
+)
+
+// allFields is the value of --json given without fields.
+const allFields = "*"
+
+// normalizeJSONArgs rewrites "--json FIELDS" as "--json=FIELDS", so that
+// --json takes a field list like gh while it can still be given alone.
+// The next argument is taken as fields if it is a comma-separated list or a
+// field name, rather than a pull request.
+func normalizeJSONArgs(args []string) []string {
+	fields := review.JSONFields()
+	normalized := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			normalized = append(normalized, args[i:]...)
+			break
+		}
+		if args[i] == "--json" && i+1 < len(args) {
+			next := args[i+1]
+			if !strings.HasPrefix(next, "-") && (strings.Contains(next, ",") || slices.Contains(fields, next)) {
+				normalized = append(normalized, "--json="+next)
+				i++
+				continue
+			}
+		}
+		normalized = append(normalized, args[i])
+	}
+	return normalized
+}
+
+// outputFormats are the values accepted by --format.
+var outputFormats = []string{formatMarkdown, formatJSON, formatGitHubAnnotations, formatSARIF, formatJUnit, formatNDJSON, formatCSV, formatTSV, formatHTML}
+
+// renderResults writes results to stdout in the selected output format.
+// JSON based output is wrapped in envelope, if any, or replaced with stats
+// with --stats, and documents are titled title.
+func renderResults(results []review.UnresolvedComment, stats review.Stats, envelope *review.Envelope, title string) error {
+	// JSON based output with only the fields selected by --json.
+	var v any = results
+	if len(jsonFields) > 0 {
+		selected, err := review.SelectFields(results, jsonFields)
+		if err != nil {
+			return err
+		}
+		v = selected
+	}
+	if envelope != nil {
+		envelope.Items = v
+		v = envelope
+	}
+	if showStats {
+		v = stats
+	}
+
+	p := termenv.NewOutput(os.Stdout, termenv.WithColorCache(true))
+	colorEnabled := p.Profile != termenv.Ascii
+	switch {
+	case templateFlag != "":
+		return output.RenderTemplate(os.Stdout, v, templateFlag, output.DetectWidth(widthFlag), colorEnabled)
+	case jqFlag != "":
+		return output.RenderJQ(os.Stdout, v, jqFlag, colorEnabled)
+	}
+
+	switch outputFormat {
+	case formatJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+	case formatGitHubAnnotations:
+		output.RenderGitHubAnnotations(os.Stdout, results)
+	case formatSARIF:
+		return output.RenderSARIF(os.Stdout, results)
+	case formatJUnit:
+		return output.RenderJUnit(os.Stdout, results)
+	case formatCSV:
+		return output.RenderCSV(os.Stdout, results, ',')
+	case formatTSV:
+		return output.RenderCSV(os.Stdout, results, '\t')
+	case formatHTML:
+		return output.RenderHTML(os.Stdout, results, title)
+	default:
+		w := output.DetectWidth(widthFlag)
+		opts := []output.Option{output.WithSummary(stats)}
+		if showThread {
+			opts = append(opts, output.WithReplies(lastReplies))
+		}
+		if explain {
+			opts = append(opts, output.WithExplain())
+		}
+		if showDiff {
+			opts = append(opts, output.WithDiff(diffLines))
+		}
+		if groupBy != "" {
+			opts = append(opts, output.WithGroupBy(groupBy))
+		}
+		if sortBy != "" {
+			opts = append(opts, output.WithSort(sortBy))
+		}
+		output.RenderMarkdown(os.Stdout, results, p, w, opts...)
+	}
+	return nil
+}
+
+// fetchReviewData resolves the PR and fetches its review data.
+func fetchReviewData(ctx context.Context, s *spinner.Spinner, args []string) (*prContext, *gh.Client, *review.Data, error) {
+	// Resolve PR context via gh CLI.
+	s.Suffix = " Resolving PR..."
+	s.Start()
+	prInfo, err := resolvePR(args, flagRepoSelector)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	slog.Info("resolved PR", "owner", prInfo.owner, "repo", prInfo.repo, "number", prInfo.number)
+
+	// Create GitHub GraphQL client.
+	ghClient, err := gh.New()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Fetch review data.
+	s.Suffix = " Fetching review data..."
+	data, err := ghClient.FetchReviews(ctx, prInfo.owner, prInfo.repo, prInfo.number)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	slog.Info("fetched review data", "threads", len(data.Threads), "pr_comments", len(data.PRComments))
+
+	return prInfo, ghClient, data, nil
+}
+
+// fetchFiles fetches the head contents of paths into data.Files.
+func fetchFiles(ctx context.Context, s *spinner.Spinner, ghClient *gh.Client, prInfo *prContext, data *review.Data, paths []string) error {
+	if len(paths) == 0 || data.HeadCommitID == "" {
+		return nil
+	}
+	s.Suffix = " Fetching file contents..."
+	files, err := ghClient.FetchFiles(ctx, prInfo.owner, prInfo.repo, data.HeadCommitID, paths)
+	if err != nil {
+		return err
+	}
+	data.Files = files
+	return nil
+}
+
+// autoResolveThreads resolves on GitHub the threads that the analysis found
+// addressed but that are still open, reporting each one on stderr.
+func autoResolveThreads(ctx context.Context, ghClient *gh.Client, results []review.UnresolvedComment) error {
+	failed := 0
+	for _, t := range review.AutoResolvable(results, minConfidence) {
+		label := fmt.Sprintf("%s (%s)", t.ThreadID, t.Path)
+		if dryRun {
+			fmt.Fprintf(os.Stderr, "Would resolve %s: %s\n", label, t.Reason)
+			continue
+		}
+		if autoResolveReply {
+			body := fmt.Sprintf("Resolved automatically by %s: %s", version.Name, t.Reason)
+			if _, err := ghClient.ReplyToThread(ctx, t.ThreadID, body); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+				failed++
+				continue
+			}
+		}
+		if err := ghClient.ResolveThread(ctx, t.ThreadID); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "✓ Resolved %s: %s\n", label, t.Reason)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to auto-resolve %d thread(s)", failed)
+	}
+	return nil
+}
+
+func setupLogger() {
+	level := slog.LevelError
+	if verbose {
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+}
+
+func newSpinner() *spinner.Spinner {
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond, spinner.WithWriter(colorable.NewColorableStderr()))
+	_ = s.Color("fgHiMagenta")
+	return s
+}
+
 type prContext struct {
 	owner  string
 	repo   string
@@ -192,9 +584,47 @@ func resolvePR(args []string, repoSelector string) (*prContext, error) {
 	}, nil
 }
 
+// resolveRepo returns the owner and name of the repository selected with
+// --repo, or of the repository in the current directory.
+func resolveRepo(repoSelector string) (string, string, error) {
+	if repoSelector != "" {
+		parts := strings.Split(strings.TrimSuffix(repoSelector, "/"), "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+			return "", "", fmt.Errorf("invalid repository %q: expected the [HOST/]OWNER/REPO format", repoSelector)
+		}
+		return parts[len(parts)-2], parts[len(parts)-1], nil
+	}
+
+	out, err := exec.Command("gh", "repo", "view", "--json", "owner,name").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", "", fmt.Errorf("gh repo view failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", "", fmt.Errorf("gh repo view failed: %w", err)
+	}
+
+	var result struct {
+		Name  string                 `json:"name"`
+		Owner struct{ Login string } `json:"owner"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return "", "", fmt.Errorf("failed to parse gh repo view output: %w", err)
+	}
+	if result.Owner.Login == "" || result.Name == "" {
+		return "", "", fmt.Errorf("could not determine repository owner/name")
+	}
+	return result.Owner.Login, result.Name, nil
+}
+
 // Execute runs the root command.
 func Execute() {
+	rootCmd.SetArgs(normalizeJSONArgs(os.Args[1:]))
 	err := rootCmd.Execute()
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -202,20 +632,34 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&flagRepoSelector, "repo", "R", "", "Select another repository using the [HOST/]OWNER/REPO format")
+	rootCmd.PersistentFlags().StringVarP(&flagRepoSelector, "repo", "R", "", "Select another repository using the [HOST/]OWNER/REPO format")
+	rootCmd.Flags().StringVarP(&templateFlag, "template", "t", "", "Format JSON output using a Go template; see \"gh help formatting\"")
+	rootCmd.Flags().StringVarP(&jqFlag, "jq", "q", "", "Filter JSON output using a jq expression")
+	rootCmd.MarkFlagsMutuallyExclusive("template", "jq")
 	rootCmd.Flags().BoolVarP(&showAll, "all", "a", false, "Show all review comments including resolved ones")
-	rootCmd.Flags().StringVar(&copilotModel, "copilot-model", "claude-haiku-4.5", "Copilot model to use for classification")
-	rootCmd.Flags().BoolVar(&verbose, "verbose", false, "Verbose output")
-	rootCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+	addCopilotModelFlag(rootCmd)
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output")
+	rootCmd.Flags().StringSliceVar(&jsonFields, "json", nil, "Output JSON with the specified `fields` (all fields if omitted; shorthand for --format json)")
+	rootCmd.Flags().Lookup("json").NoOptDefVal = allFields
+	rootCmd.Flags().IntVar(&batchSize, "batch-size", 0, fmt.Sprintf("Classify comments in batches of N (0 for all at once, or %d with --format ndjson)", defaultStreamBatchSize))
+	rootCmd.Flags().BoolVar(&jsonEnvelope, "json-envelope", false, "Wrap JSON output in an object with the pull request and run metadata")
+	rootCmd.Flags().BoolVar(&showStats, "stats", false, "Output the counts of the results by status, category, author and file instead of the results with --json")
+	rootCmd.Flags().StringVar(&outputFormat, "format", formatMarkdown, fmt.Sprintf("Output format (%s)", strings.Join(outputFormats, ", ")))
 	rootCmd.Flags().IntVarP(&widthFlag, "width", "w", 0, "Output width (0 for auto-detect)")
-
-	_ = rootCmd.RegisterFlagCompletionFunc("copilot-model", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		models, err := review.ListCopilotModels(rootCmd.Context())
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveError
-		}
-		return models, cobra.ShellCompDirectiveNoFileComp
-	})
+	rootCmd.Flags().BoolVar(&showThread, "thread", false, "Show the replies of each thread")
+	rootCmd.Flags().BoolVar(&showThread, "full", false, "Alias for --thread")
+	rootCmd.Flags().IntVar(&lastReplies, "last-replies", 0, "Show only the last N replies of each thread with --thread (0 for all)")
+	rootCmd.Flags().StringVar(&groupBy, "group-by", "", fmt.Sprintf("Group the results by (%s; default: path)", strings.Join(output.GroupKeys, ", ")))
+	rootCmd.Flags().StringVar(&sortBy, "sort", "", fmt.Sprintf("Sort the results by (%s; default: fetch order)", strings.Join(output.SortKeys, ", ")))
+	rootCmd.Flags().BoolVar(&showDiff, "diff", false, "Show the diff hunk lines nearest the commented line of each thread")
+	rootCmd.Flags().IntVar(&diffLines, "diff-lines", 4, "Number of diff hunk lines to show with --diff")
+	rootCmd.Flags().BoolVar(&explain, "explain", false, "Show the classifier input and the raw model response for each item")
+	rootCmd.Flags().BoolVar(&autoResolve, "auto-resolve", false, "Resolve threads on GitHub that are found addressed but still open")
+	rootCmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.9, "Minimum classifier confidence (0.0-1.0) required by --auto-resolve")
+	rootCmd.Flags().BoolVar(&autoResolveReply, "auto-resolve-reply", false, "Reply to each auto-resolved thread with the reason")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the threads --auto-resolve would resolve without resolving them")
+	addExitStatusFlags(rootCmd)
+	rootCmd.Flags().BoolVar(&draftReplies, "draft-replies", false, "Draft a reply for each unanswered question using Copilot")
 
 	// Hide the default completion command.
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
@@ -231,3 +675,20 @@ func init() {
 	rootCmd.SilenceErrors = true
 }
 
+// addCopilotModelFlag adds --copilot-model, completed with the available models, to cmd.
+func addCopilotModelFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&copilotModel, "copilot-model", "claude-haiku-4.5", "Copilot model to use for classification")
+	_ = cmd.RegisterFlagCompletionFunc("copilot-model", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		models, err := review.ListCopilotModels(cmd.Context())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return models, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// addExitStatusFlags adds --exit-status and --fail-on to cmd.
+func addExitStatusFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&exitStatus, "exit-status", false, fmt.Sprintf("Exit with status %d if unresolved comments remain", exitCodeUnresolved))
+	cmd.Flags().StringSliceVar(&failOn, "fail-on", nil, "Categories that fail --exit-status, as CATEGORY or CATEGORY=N to tolerate N unresolved (default: all)")
+}
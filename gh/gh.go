@@ -3,16 +3,22 @@ package gh
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/go-github/v79/github"
 	"github.com/k1LoW/gh-pr-reviews/review"
 	"github.com/k1LoW/go-github-client/v79/factory"
 	"github.com/shurcooL/githubv4"
 )
 
-// Client is a GitHub GraphQL API client for fetching PR review data.
+// Client is a GitHub API client for fetching and updating PR review data.
 type Client struct {
-	v4 *githubv4.Client
+	v4   *githubv4.Client
+	rest *github.Client
 }
 
 // New creates a new Client.
@@ -22,20 +28,27 @@ func New() (*Client, error) {
 		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
 	}
 	v4Client := githubv4.NewClient(ghClient.Client())
-	return &Client{v4: v4Client}, nil
+	return &Client{v4: v4Client, rest: ghClient}, nil
 }
 
 type reviewThreadsQuery struct {
 	Repository struct {
 		PullRequest struct {
+			Title         string
+			HeadRefOid    string
 			ReviewThreads struct {
 				Nodes []struct {
-					ID         string
-					IsResolved bool
-					IsOutdated bool
-					Path       string
-					Line       *int
-					Comments   struct {
+					ID                string
+					IsResolved        bool
+					IsOutdated        bool
+					Path              string
+					Line              *int
+					StartLine         *int
+					OriginalLine      *int
+					OriginalStartLine *int
+					DiffSide          string
+					SubjectType       string
+					Comments          struct {
 						Nodes []struct {
 							ID         string
 							DatabaseId int64
@@ -64,11 +77,12 @@ type prCommentsQuery struct {
 		PullRequest struct {
 			Comments struct {
 				Nodes []struct {
-					ID        string
-					Body      string
-					Author    struct{ Login string }
-					CreatedAt time.Time
-					URL       string `graphql:"url"`
+					ID         string
+					DatabaseId int64
+					Body       string
+					Author     struct{ Login string }
+					CreatedAt  time.Time
+					URL        string `graphql:"url"`
 				}
 				PageInfo struct {
 					HasNextPage bool
@@ -96,13 +110,20 @@ func (c *Client) FetchReviews(ctx context.Context, owner, repo string, number in
 		if err := c.v4.Query(ctx, &q, variables); err != nil {
 			return nil, fmt.Errorf("failed to fetch review threads: %w", err)
 		}
+		data.Title = q.Repository.PullRequest.Title
+		data.HeadCommitID = q.Repository.PullRequest.HeadRefOid
 		for _, node := range q.Repository.PullRequest.ReviewThreads.Nodes {
 			thread := review.Thread{
-				ID:         node.ID,
-				IsResolved: node.IsResolved,
-				IsOutdated: node.IsOutdated,
-				Path:       node.Path,
-				Line:       node.Line,
+				ID:                node.ID,
+				IsResolved:        node.IsResolved,
+				IsOutdated:        node.IsOutdated,
+				Path:              node.Path,
+				Line:              node.Line,
+				StartLine:         node.StartLine,
+				OriginalLine:      node.OriginalLine,
+				OriginalStartLine: node.OriginalStartLine,
+				DiffSide:          node.DiffSide,
+				SubjectType:       node.SubjectType,
 			}
 			for _, c := range node.Comments.Nodes {
 				thread.Comments = append(thread.Comments, review.Comment{
@@ -140,11 +161,12 @@ func (c *Client) FetchReviews(ctx context.Context, owner, repo string, number in
 		}
 		for _, node := range q.Repository.PullRequest.Comments.Nodes {
 			data.PRComments = append(data.PRComments, review.Comment{
-				ID:        node.ID,
-				Body:      node.Body,
-				Author:    node.Author.Login,
-				CreatedAt: node.CreatedAt,
-				URL:       node.URL,
+				ID:         node.ID,
+				DatabaseID: node.DatabaseId,
+				Body:       node.Body,
+				Author:     node.Author.Login,
+				CreatedAt:  node.CreatedAt,
+				URL:        node.URL,
 			})
 		}
 		if !q.Repository.PullRequest.Comments.PageInfo.HasNextPage {
@@ -156,3 +178,190 @@ func (c *Client) FetchReviews(ctx context.Context, owner, repo string, number in
 
 	return data, nil
 }
+
+type fileContentQuery struct {
+	Repository struct {
+		Object struct {
+			Blob struct {
+				Text *string
+			} `graphql:"... on Blob"`
+		} `graphql:"object(expression: $expression)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+// FetchFiles fetches the contents of the given paths at the given commit.
+// Paths that do not exist or are binary are omitted from the result.
+func (c *Client) FetchFiles(ctx context.Context, owner, repo, commitID string, paths []string) (map[string]string, error) {
+	files := make(map[string]string, len(paths))
+	for _, p := range paths {
+		var q fileContentQuery
+		variables := map[string]any{
+			"owner":      githubv4.String(owner),
+			"repo":       githubv4.String(repo),
+			"expression": githubv4.String(commitID + ":" + p),
+		}
+		if err := c.v4.Query(ctx, &q, variables); err != nil {
+			return nil, fmt.Errorf("failed to fetch file %s: %w", p, err)
+		}
+		if q.Repository.Object.Blob.Text == nil {
+			continue
+		}
+		files[p] = *q.Repository.Object.Blob.Text
+	}
+	return files, nil
+}
+
+// ResolveThread marks a review thread as resolved.
+func (c *Client) ResolveThread(ctx context.Context, threadID string) error {
+	var m struct {
+		ResolveReviewThread struct {
+			Thread struct {
+				ID         string
+				IsResolved bool
+			}
+		} `graphql:"resolveReviewThread(input: $input)"`
+	}
+	input := githubv4.ResolveReviewThreadInput{ThreadID: githubv4.ID(threadID)}
+	if err := c.v4.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("failed to resolve thread %s: %w", threadID, err)
+	}
+	return nil
+}
+
+// UnresolveThread marks a review thread as unresolved.
+func (c *Client) UnresolveThread(ctx context.Context, threadID string) error {
+	var m struct {
+		UnresolveReviewThread struct {
+			Thread struct {
+				ID         string
+				IsResolved bool
+			}
+		} `graphql:"unresolveReviewThread(input: $input)"`
+	}
+	input := githubv4.UnresolveReviewThreadInput{ThreadID: githubv4.ID(threadID)}
+	if err := c.v4.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("failed to unresolve thread %s: %w", threadID, err)
+	}
+	return nil
+}
+
+// ReplyToThread adds a reply to a review thread and returns the URL of the reply.
+func (c *Client) ReplyToThread(ctx context.Context, threadID, body string) (string, error) {
+	var m struct {
+		AddPullRequestReviewThreadReply struct {
+			Comment struct {
+				URL string `graphql:"url"`
+			}
+		} `graphql:"addPullRequestReviewThreadReply(input: $input)"`
+	}
+	input := githubv4.AddPullRequestReviewThreadReplyInput{
+		PullRequestReviewThreadID: githubv4.ID(threadID),
+		Body:                      githubv4.String(body),
+	}
+	if err := c.v4.Mutate(ctx, &m, input, nil); err != nil {
+		return "", fmt.Errorf("failed to reply to thread %s: %w", threadID, err)
+	}
+	return m.AddPullRequestReviewThreadReply.Comment.URL, nil
+}
+
+// ReplyToComment replies to the comment with the given REST API ID and returns
+// the URL of the reply. A PR comment gets a PR comment quoting it followed by
+// body, and a review comment (the first comment of a thread, as in comment_id)
+// gets a reply in its thread.
+func (c *Client) ReplyToComment(ctx context.Context, owner, repo string, commentID int64, body string) (string, error) {
+	orig, resp, err := c.rest.Issues.GetComment(ctx, owner, repo, commentID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return c.replyToReviewComment(ctx, owner, repo, commentID, body)
+		}
+		return "", fmt.Errorf("failed to fetch comment %d: %w", commentID, err)
+	}
+	number, err := strconv.Atoi(orig.GetIssueURL()[strings.LastIndex(orig.GetIssueURL(), "/")+1:])
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the pull request of comment %d: %w", commentID, err)
+	}
+	reply := &github.IssueComment{Body: github.Ptr(quote(orig.GetBody()) + "\n\n" + body)}
+	created, _, err := c.rest.Issues.CreateComment(ctx, owner, repo, number, reply)
+	if err != nil {
+		return "", fmt.Errorf("failed to reply to comment %d: %w", commentID, err)
+	}
+	return created.GetHTMLURL(), nil
+}
+
+// UpsertIssueComment updates the first comment on the issue or pull request
+// that starts with marker and is written by the authenticated user, or by a
+// bot if the user is unknown, with body, or creates one if there is none, and
+// returns the URL of the comment. Comments of others, such as quote replies,
+// are never updated.
+func (c *Client) UpsertIssueComment(ctx context.Context, owner, repo string, number int, marker, body string) (string, error) {
+	// The authenticated user is unknown to GitHub App tokens, whose comments are
+	// written by a bot.
+	var login string
+	if u, _, err := c.rest.Users.Get(ctx, ""); err == nil {
+		login = u.GetLogin()
+	}
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := c.rest.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to list comments of #%d: %w", number, err)
+		}
+		for _, ic := range comments {
+			if !strings.HasPrefix(ic.GetBody(), marker) {
+				continue
+			}
+			// Match on the login when it is known, since a comment of another bot,
+			// such as one written with GITHUB_TOKEN before, cannot be edited.
+			if u := ic.GetUser(); (login != "" && u.GetLogin() != login) || (login == "" && u.GetType() != "Bot") {
+				continue
+			}
+			updated, _, err := c.rest.Issues.EditComment(ctx, owner, repo, ic.GetID(), &github.IssueComment{Body: github.Ptr(body)})
+			if err != nil {
+				return "", fmt.Errorf("failed to update comment %d: %w", ic.GetID(), err)
+			}
+			return updated.GetHTMLURL(), nil
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	created, _, err := c.rest.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: github.Ptr(body)})
+	if err != nil {
+		return "", fmt.Errorf("failed to comment on #%d: %w", number, err)
+	}
+	return created.GetHTMLURL(), nil
+}
+
+// replyToReviewComment replies in the thread of the review comment with the given REST API ID.
+func (c *Client) replyToReviewComment(ctx context.Context, owner, repo string, commentID int64, body string) (string, error) {
+	orig, resp, err := c.rest.PullRequests.GetComment(ctx, owner, repo, commentID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", fmt.Errorf("comment %d is neither a PR comment nor a review comment of %s/%s", commentID, owner, repo)
+		}
+		return "", fmt.Errorf("failed to fetch review comment %d: %w", commentID, err)
+	}
+	number, err := strconv.Atoi(orig.GetPullRequestURL()[strings.LastIndex(orig.GetPullRequestURL(), "/")+1:])
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the pull request of review comment %d: %w", commentID, err)
+	}
+	created, _, err := c.rest.PullRequests.CreateCommentInReplyTo(ctx, owner, repo, number, body, commentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to reply to review comment %d: %w", commentID, err)
+	}
+	return created.GetHTMLURL(), nil
+}
+
+var htmlCommentRegexp = regexp.MustCompile(`(?s)<!--.*?-->\n?`)
+
+// quote formats s as a Markdown block quote. HTML comments, which are not
+// rendered and may be markers of other tools, are left out.
+func quote(s string) string {
+	s = htmlCommentRegexp.ReplaceAllString(s, "")
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,271 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v79/github"
+	"github.com/shurcooL/githubv4"
+)
+
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// newFakeServer starts a GraphQL server that answers each request with the
+// response whose key is contained in the query, recording the requests.
+func newFakeServer(t *testing.T, responses map[string]string) (*Client, *[]graphqlRequest) {
+	t.Helper()
+	var requests []graphqlRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+		for key, resp := range responses {
+			if strings.Contains(req.Query, key) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(resp))
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message":"unexpected query"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return &Client{v4: githubv4.NewEnterpriseClient(srv.URL, srv.Client())}, &requests
+}
+
+// newFakeRESTClient returns a Client whose REST API is served by mux.
+func newFakeRESTClient(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	rest := github.NewClient(srv.Client())
+	base, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest.BaseURL = base
+	return &Client{rest: rest}
+}
+
+func TestResolveThread(t *testing.T) {
+	c, requests := newFakeServer(t, map[string]string{
+		"resolveReviewThread": `{"data":{"resolveReviewThread":{"thread":{"id":"PRRT_1","isResolved":true}}}}`,
+	})
+
+	if err := c.ResolveThread(context.Background(), "PRRT_1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(*requests))
+	}
+	req := (*requests)[0]
+	if !strings.HasPrefix(req.Query, "mutation") || strings.Contains(req.Query, "unresolveReviewThread") {
+		t.Errorf("unexpected query: %s", req.Query)
+	}
+	input, ok := req.Variables["input"].(map[string]any)
+	if !ok || input["threadId"] != "PRRT_1" {
+		t.Errorf("unexpected variables: %v", req.Variables)
+	}
+}
+
+func TestUnresolveThread(t *testing.T) {
+	c, requests := newFakeServer(t, map[string]string{
+		"unresolveReviewThread": `{"data":{"unresolveReviewThread":{"thread":{"id":"PRRT_1","isResolved":false}}}}`,
+	})
+
+	if err := c.UnresolveThread(context.Background(), "PRRT_1"); err != nil {
+		t.Fatal(err)
+	}
+	input, ok := (*requests)[0].Variables["input"].(map[string]any)
+	if !ok || input["threadId"] != "PRRT_1" {
+		t.Errorf("unexpected variables: %v", (*requests)[0].Variables)
+	}
+}
+
+func TestResolveThreadError(t *testing.T) {
+	c, _ := newFakeServer(t, map[string]string{
+		"resolveReviewThread": `{"data":null,"errors":[{"message":"Could not resolve to a node with the global id of 'PRRT_x'"}]}`,
+	})
+
+	err := c.ResolveThread(context.Background(), "PRRT_x")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "PRRT_x") {
+		t.Errorf("expected error to mention the thread ID, got %v", err)
+	}
+}
+
+func TestReplyToThread(t *testing.T) {
+	c, requests := newFakeServer(t, map[string]string{
+		"addPullRequestReviewThreadReply": `{"data":{"addPullRequestReviewThreadReply":{"comment":{"url":"https://github.com/o/r/pull/1#discussion_r2"}}}}`,
+	})
+
+	got, err := c.ReplyToThread(context.Background(), "PRRT_1", "Done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "https://github.com/o/r/pull/1#discussion_r2" {
+		t.Errorf("unexpected URL: %s", got)
+	}
+	input, ok := (*requests)[0].Variables["input"].(map[string]any)
+	if !ok || input["pullRequestReviewThreadId"] != "PRRT_1" || input["body"] != "Done" {
+		t.Errorf("unexpected variables: %v", (*requests)[0].Variables)
+	}
+}
+
+func TestReplyToComment(t *testing.T) {
+	var posted map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/o/r/issues/comments/42", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42,"body":"Can we add tests?\nAnd docs?","issue_url":"https://api.github.com/repos/o/r/issues/7"}`))
+	})
+	mux.HandleFunc("POST /repos/o/r/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":43,"html_url":"https://github.com/o/r/pull/7#issuecomment-43"}`))
+	})
+	c := newFakeRESTClient(t, mux)
+
+	got, err := c.ReplyToComment(context.Background(), "o", "r", 42, "Added both.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "https://github.com/o/r/pull/7#issuecomment-43" {
+		t.Errorf("unexpected URL: %s", got)
+	}
+	if want := "> Can we add tests?\n> And docs?\n\nAdded both."; posted["body"] != want {
+		t.Errorf("got body %q, want %q", posted["body"], want)
+	}
+}
+
+func TestReplyToReviewComment(t *testing.T) {
+	var posted map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/o/r/issues/comments/42", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /repos/o/r/pulls/comments/42", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42,"body":"Rename this","pull_request_url":"https://api.github.com/repos/o/r/pulls/7"}`))
+	})
+	mux.HandleFunc("POST /repos/o/r/pulls/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":44,"html_url":"https://github.com/o/r/pull/7#discussion_r44"}`))
+	})
+	c := newFakeRESTClient(t, mux)
+
+	got, err := c.ReplyToComment(context.Background(), "o", "r", 42, "Renamed.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "https://github.com/o/r/pull/7#discussion_r44" {
+		t.Errorf("unexpected URL: %s", got)
+	}
+	if posted["body"] != "Renamed." || posted["in_reply_to"] != float64(42) {
+		t.Errorf("unexpected request: %v", posted)
+	}
+
+	_, err = c.ReplyToComment(context.Background(), "o", "r", 99, "Renamed.")
+	if err == nil || !strings.Contains(err.Error(), "neither a PR comment nor a review comment") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	got := quote("first\n\nthird\n")
+	want := "> first\n>\n> third"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = quote("<!-- gh-pr-reviews -->\n- [ ] item <!-- note -->\n")
+	want = "> - [ ] item"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestUpsertIssueComment(t *testing.T) {
+	tests := []struct {
+		name       string
+		login      string
+		existing   string
+		wantMethod string
+	}{
+		{"create", "me", `[{"id":1,"body":"LGTM","user":{"login":"alice","type":"User"}}]`, http.MethodPost},
+		{"update", "me", `[{"id":1,"body":"LGTM","user":{"login":"alice","type":"User"}},{"id":2,"body":"<!-- marker -->\nold","user":{"login":"me","type":"User"}}]`, http.MethodPatch},
+		{"update bot", "", `[{"id":2,"body":"<!-- marker -->\nold","user":{"login":"github-actions[bot]","type":"Bot"}}]`, http.MethodPatch},
+		{"bot with user token", "me", `[{"id":2,"body":"<!-- marker -->\nold","user":{"login":"github-actions[bot]","type":"Bot"}}]`, http.MethodPost},
+		{"quote reply", "me", `[{"id":3,"body":"> <!-- marker -->\n> old\n\nThanks","user":{"login":"alice","type":"User"}}]`, http.MethodPost},
+		{"other user", "me", `[{"id":3,"body":"<!-- marker -->\nmine","user":{"login":"alice","type":"User"}}]`, http.MethodPost},
+		{"other user with app token", "", `[{"id":3,"body":"<!-- marker -->\nmine","user":{"login":"alice","type":"User"}}]`, http.MethodPost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+			var posted map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /user", func(w http.ResponseWriter, _ *http.Request) {
+				if tt.login == "" {
+					// GitHub App tokens cannot read the authenticated user.
+					http.Error(w, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"login":"` + tt.login + `"}`))
+			})
+			mux.HandleFunc("GET /repos/o/r/issues/7/comments", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.existing))
+			})
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":2,"html_url":"https://github.com/o/r/pull/7#issuecomment-2"}`))
+			}
+			mux.HandleFunc("POST /repos/o/r/issues/7/comments", handler)
+			mux.HandleFunc("PATCH /repos/o/r/issues/comments/2", handler)
+			c := newFakeRESTClient(t, mux)
+
+			got, err := c.UpsertIssueComment(context.Background(), "o", "r", 7, "<!-- marker -->", "<!-- marker -->\nnew")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "https://github.com/o/r/pull/7#issuecomment-2" {
+				t.Errorf("unexpected URL: %s", got)
+			}
+			if method != tt.wantMethod {
+				t.Errorf("got method %s, want %s", method, tt.wantMethod)
+			}
+			if posted["body"] != "<!-- marker -->\nnew" {
+				t.Errorf("unexpected request: %v", posted)
+			}
+		})
+	}
+}
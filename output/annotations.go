@@ -0,0 +1,59 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+// RenderGitHubAnnotations writes the unresolved items of results as GitHub
+// Actions workflow commands: a warning for each thread, annotated on its
+// lines in the head, and a notice for each PR comment.
+func RenderGitHubAnnotations(w io.Writer, results []review.UnresolvedComment) {
+	for _, c := range review.Unresolved(results) {
+		command := "notice"
+		var props []string
+		if c.Type == "thread" {
+			command = "warning"
+			props = append(props, "file="+escapeProperty(c.Path))
+			if start, end, ok := headLines(c); ok {
+				props = append(props, fmt.Sprintf("line=%d", start))
+				if end > start {
+					props = append(props, fmt.Sprintf("endLine=%d", end))
+				}
+			}
+		}
+		props = append(props, "title="+escapeProperty(c.Category))
+
+		message := c.Body
+		if c.URL != "" {
+			message += "\n\n" + c.URL
+		}
+		fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(props, ","), escapeData(message))
+	}
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// headLines returns the lines of the head a thread is attached to. Outdated
+// lines, lines on the base side and file-level comments have none.
+func headLines(c review.UnresolvedComment) (int, int, bool) {
+	if c.Line == nil || c.DiffSide == "LEFT" || c.SubjectType == "FILE" {
+		return 0, 0, false
+	}
+	start := *c.Line
+	if c.StartLine != nil && *c.StartLine < start {
+		start = *c.StartLine
+	}
+	return start, *c.Line, true
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderGitHubAnnotations(t *testing.T) {
+	line, start := 42, 40
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "src/a,b.go", Line: &line, StartLine: &start, Author: "alice", Body: "Use 100%\nwrapping", URL: "https://example.com/1", Category: "issue"},
+		{Type: "thread", Path: "main.go", Line: &line, Author: "bob", Body: "Rename", Category: "nitpick"},
+		{Type: "thread", Path: "old.go", OriginalLine: &line, Author: "bob", Body: "Outdated", Category: "suggestion"},
+		{Type: "thread", Path: "main.go", Line: &line, Author: "bob", Body: "LGTM", Category: "approval", Resolved: true},
+		{Type: "comment", Author: "carol", Body: "Tests?", Category: "question"},
+	}
+
+	var buf bytes.Buffer
+	RenderGitHubAnnotations(&buf, results)
+	want := "::warning file=src/a%2Cb.go,line=40,endLine=42,title=issue::Use 100%25%0Awrapping%0A%0Ahttps://example.com/1\n" +
+		"::warning file=main.go,line=42,title=nitpick::Rename\n" +
+		"::warning file=old.go,title=suggestion::Outdated\n" +
+		"::notice title=question::Tests?\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
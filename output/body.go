@@ -0,0 +1,109 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/termenv"
+)
+
+// bodyRenderer renders comment bodies as Markdown for the terminal.
+type bodyRenderer struct {
+	r     *glamour.TermRenderer
+	width int
+}
+
+// newBodyRenderer returns a renderer for the color profile of p that wraps
+// text at width. Code blocks are not wrapped. If glamour fails, it returns
+// the error together with a renderer that only wraps text.
+func newBodyRenderer(p *termenv.Output, width int) (*bodyRenderer, error) {
+	var style ansi.StyleConfig
+	switch {
+	case p.Profile == termenv.Ascii:
+		style = styles.NoTTYStyleConfig
+	case p.HasDarkBackground():
+		style = styles.DarkStyleConfig
+	default:
+		style = styles.LightStyleConfig
+	}
+	// Align bodies with the rest of the output.
+	zero := uint(0)
+	style.Document.Margin = &zero
+	style.Document.BlockPrefix = ""
+	style.Document.BlockSuffix = ""
+
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStyles(style),
+		glamour.WithWordWrap(width),
+		glamour.WithColorProfile(p.Profile),
+	)
+	if err != nil {
+		return &bodyRenderer{width: width}, fmt.Errorf("failed to create markdown renderer: %w", err)
+	}
+	return &bodyRenderer{r: r, width: width}, nil
+}
+
+// render renders the body of c, showing suggestion blocks as diffs against the commented lines.
+func (b *bodyRenderer) render(c review.UnresolvedComment) string {
+	return b.renderMarkdown(suggestionsAsDiff(c.Body, commentedLines(c)))
+}
+
+// renderMarkdown renders body, such as a reply, as Markdown.
+func (b *bodyRenderer) renderMarkdown(body string) string {
+	if b.r == nil {
+		return wordwrap.String(body, b.width)
+	}
+	out, err := b.r.Render(body)
+	if err != nil {
+		return wordwrap.String(body, b.width)
+	}
+	lines := strings.Split(strings.Trim(out, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commentedLines returns the lines of the head a thread comments on, taken from
+// the end of its diff hunk, or nil if unknown.
+func commentedLines(c review.UnresolvedComment) []string {
+	line, start := c.Line, c.StartLine
+	if line == nil {
+		line, start = c.OriginalLine, c.OriginalStartLine
+	}
+	if line == nil || c.DiffSide == "LEFT" {
+		return nil
+	}
+	n := 1
+	if start != nil && *start < *line {
+		n = *line - *start + 1
+	}
+	return review.HunkTail(c.DiffHunk, n)
+}
+
+// suggestionsAsDiff rewrites ```suggestion blocks in body as diff blocks that
+// remove original and add the suggested lines.
+func suggestionsAsDiff(body string, original []string) string {
+	lines := strings.Split(body, "\n")
+	var out []string
+	next := 0
+	for _, b := range review.FindSuggestionBlocks(lines) {
+		out = append(out, lines[next:b.Open]...)
+		out = append(out, b.Indent+b.Fence+"diff")
+		for _, l := range original {
+			out = append(out, b.Indent+"-"+l)
+		}
+		for _, l := range lines[b.Open+1 : b.Close] {
+			out = append(out, b.Indent+"+"+strings.TrimPrefix(l, b.Indent))
+		}
+		out = append(out, lines[b.Close])
+		next = b.Close + 1
+	}
+	out = append(out, lines[next:]...)
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+const maxSummaryLen = 100
+
+// RenderChecklist writes the unresolved items of results as a GitHub Markdown
+// checklist with links, suitable for a PR comment or a job summary.
+func RenderChecklist(w io.Writer, results []review.UnresolvedComment) {
+	unresolved := review.Unresolved(results)
+
+	fmt.Fprintln(w, "### Unresolved review comments")
+	fmt.Fprintln(w)
+	if len(unresolved) == 0 {
+		fmt.Fprintln(w, "No unresolved review comments found.")
+		return
+	}
+	fmt.Fprintf(w, "%d unresolved review %s.\n\n", len(unresolved), plural(len(unresolved), "comment", "comments"))
+
+	for _, c := range unresolved {
+		target := "PR comment"
+		if c.Type == "thread" {
+			target = inlineCode(c.Path)
+			if loc := location(c); loc != "" {
+				target += " " + loc
+			}
+		}
+		if c.URL != "" {
+			target = fmt.Sprintf("[%s](%s)", target, c.URL)
+		}
+		// The author is not @-mentioned, so that reviewers are not notified
+		// on every update, and the summary is a code span, so that its
+		// Markdown does not break the list.
+		fmt.Fprintf(w, "- [ ] **%s** %s by %s: %s\n", c.Category, target, inlineCode(c.Author), inlineCode(summarize(c.Body)))
+	}
+}
+
+// inlineCode formats s as a Markdown code span, delimited by more backticks
+// than s contains in a row.
+func inlineCode(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+// summarize returns the first non-empty line of body, truncated to maxSummaryLen characters.
+func summarize(body string) string {
+	var line string
+	for l := range strings.SplitSeq(body, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	if r := []rune(line); len(r) > maxSummaryLen {
+		line = string(r[:maxSummaryLen-1]) + "…"
+	}
+	return line
+}
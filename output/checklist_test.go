@@ -0,0 +1,58 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderChecklist(t *testing.T) {
+	line := 42
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "main.go", Line: &line, Author: "alice", Body: "\nThis should use error wrapping\nSee docs.", URL: "https://github.com/o/r/pull/1#discussion_r1", Category: "issue"},
+		{Type: "thread", Path: "main.go", Author: "bob", Body: "LGTM", Category: "approval", Resolved: true},
+		{Type: "comment", Author: "carol", Body: "Is this covered by tests?", URL: "https://github.com/o/r/pull/1#issuecomment-2", Category: "question"},
+	}
+
+	var buf bytes.Buffer
+	RenderChecklist(&buf, results)
+	want := "### Unresolved review comments\n\n" +
+		"2 unresolved review comments.\n\n" +
+		"- [ ] **issue** [`main.go` L42](https://github.com/o/r/pull/1#discussion_r1) by `alice`: `This should use error wrapping`\n" +
+		"- [ ] **question** [PR comment](https://github.com/o/r/pull/1#issuecomment-2) by `carol`: `Is this covered by tests?`\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	RenderChecklist(&buf, results[1:2])
+	if !strings.Contains(buf.String(), "No unresolved review comments found.") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestInlineCode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "`plain`"},
+		{"use `err` here | <b>", "``use `err` here | <b>``"},
+		{"`x`", "`` `x` ``"},
+	}
+	for _, tt := range tests {
+		if got := inlineCode(tt.in); got != tt.want {
+			t.Errorf("inlineCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	long := strings.Repeat("a", 150)
+	got := summarize(long)
+	if len([]rune(got)) != maxSummaryLen || !strings.HasSuffix(got, "…") {
+		t.Errorf("unexpected summary: %q", got)
+	}
+}
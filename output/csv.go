@@ -0,0 +1,39 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+// csvColumns are the columns of RenderCSV, in order.
+var csvColumns = []string{"type", "path", "line", "author", "category", "resolved", "url", "body", "reason"}
+
+// RenderCSV writes results as comma-separated values with a header row, or
+// as tab-separated values if comma is '\t'. Fields containing separators,
+// quotes or newlines are quoted.
+func RenderCSV(w io.Writer, results []review.UnresolvedComment, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(csvColumns); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, c := range results {
+		var line string
+		if c.Line != nil {
+			line = strconv.Itoa(*c.Line)
+		}
+		record := []string{c.Type, c.Path, line, c.Author, c.Category, strconv.FormatBool(c.Resolved), c.URL, c.Body, c.Reason}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderCSV(t *testing.T) {
+	line := 42
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "main.go", Line: &line, Author: "alice", Category: "issue", URL: "https://example.com/1", Body: "Handle \"err\",\nplease", Reason: "Not addressed"},
+		{Type: "comment", Author: "bob", Category: "question", Resolved: true, Body: "Tests?\tAnd docs?"},
+	}
+
+	for _, comma := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+		if err := RenderCSV(&buf, results, comma); err != nil {
+			t.Fatal(err)
+		}
+		r := csv.NewReader(&buf)
+		r.Comma = comma
+		records, err := r.ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{
+			{"type", "path", "line", "author", "category", "resolved", "url", "body", "reason"},
+			{"thread", "main.go", "42", "alice", "issue", "false", "https://example.com/1", "Handle \"err\",\nplease", "Not addressed"},
+			{"comment", "", "", "bob", "question", "true", "", "Tests?\tAnd docs?", ""},
+		}
+		if !slices.EqualFunc(records, want, slices.Equal) {
+			t.Errorf("comma %q: got %q, want %q", comma, records, want)
+		}
+	}
+}
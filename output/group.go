@@ -0,0 +1,157 @@
+package output
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+// GroupKeys are the values accepted by WithGroupBy.
+var GroupKeys = []string{"path", "category", "author", "none"}
+
+// SortKeys are the values accepted by WithSort.
+var SortKeys = []string{"created", "updated", "line", "category", "age"}
+
+// categoryOrder is the order of categories when sorting by category, from
+// the ones that most need the attention of the pull request author.
+var categoryOrder = []string{"question", "issue", "suggestion", "nitpick", "approval", "informational"}
+
+// WithGroupBy groups the results under a header for each value of key
+// ("path", "category" or "author"), or not at all with "none".
+// By default, threads are grouped by path and PR comments are put last.
+func WithGroupBy(key string) Option {
+	return func(o *options) {
+		o.groupBy = key
+	}
+}
+
+// WithSort sorts the results by key before grouping them: "created" (oldest
+// first), "updated" (most recently commented first), "line" (by path and line),
+// "category" (questions and issues first) or "age" (longest without a new comment first).
+// By default, results are kept in fetch order.
+func WithSort(key string) Option {
+	return func(o *options) {
+		o.sortBy = key
+	}
+}
+
+type group struct {
+	title    string
+	comments []review.UnresolvedComment
+}
+
+// groupResults groups results by key, keeping the order of the first item of
+// each group.
+func groupResults(results []review.UnresolvedComment, key string) []group {
+	switch key {
+	case "none":
+		return []group{{comments: results}}
+	case "category":
+		return groupBy(results, func(c review.UnresolvedComment) string { return c.Category })
+	case "author":
+		return groupBy(results, func(c review.UnresolvedComment) string { return "@" + c.Author })
+	}
+	// Threads by path, and PR comments last.
+	var threads, prComments []review.UnresolvedComment
+	for _, r := range results {
+		if r.Type == "thread" {
+			threads = append(threads, r)
+		} else {
+			prComments = append(prComments, r)
+		}
+	}
+	groups := groupBy(threads, func(c review.UnresolvedComment) string { return c.Path })
+	if len(prComments) > 0 {
+		groups = append(groups, group{title: "PR Comments", comments: prComments})
+	}
+	return groups
+}
+
+func groupBy(results []review.UnresolvedComment, title func(review.UnresolvedComment) string) []group {
+	var groups []group
+	idx := map[string]int{}
+	for _, r := range results {
+		t := title(r)
+		i, ok := idx[t]
+		if !ok {
+			i = len(groups)
+			idx[t] = i
+			groups = append(groups, group{title: t})
+		}
+		groups[i].comments = append(groups[i].comments, r)
+	}
+	return groups
+}
+
+// sortResults returns results sorted by key. Items that compare equal keep
+// their order.
+func sortResults(results []review.UnresolvedComment, key string) []review.UnresolvedComment {
+	var compare func(a, b review.UnresolvedComment) int
+	switch key {
+	case "created":
+		compare = func(a, b review.UnresolvedComment) int { return compareTimes(a.CreatedAt, b.CreatedAt) }
+	case "updated":
+		compare = func(a, b review.UnresolvedComment) int { return compareTimes(b.LastActivityAt, a.LastActivityAt) }
+	case "age":
+		compare = func(a, b review.UnresolvedComment) int { return compareTimes(a.LastActivityAt, b.LastActivityAt) }
+	case "line":
+		compare = func(a, b review.UnresolvedComment) int {
+			return cmp.Or(
+				cmp.Compare(typeRank(a), typeRank(b)),
+				cmp.Compare(a.Path, b.Path),
+				cmp.Compare(lineOf(a), lineOf(b)),
+			)
+		}
+	case "category":
+		compare = func(a, b review.UnresolvedComment) int {
+			return cmp.Compare(categoryRank(a.Category), categoryRank(b.Category))
+		}
+	default:
+		return results
+	}
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, compare)
+	return sorted
+}
+
+// lineOf returns the line a thread comments on, or its original line if the
+// thread is outdated, or 0 if unknown.
+func lineOf(c review.UnresolvedComment) int {
+	switch {
+	case c.Line != nil:
+		return *c.Line
+	case c.OriginalLine != nil:
+		return *c.OriginalLine
+	}
+	return 0
+}
+
+// typeRank puts threads before PR comments.
+func typeRank(c review.UnresolvedComment) int {
+	if c.Type == "thread" {
+		return 0
+	}
+	return 1
+}
+
+func categoryRank(category string) int {
+	if i := slices.Index(categoryOrder, category); i >= 0 {
+		return i
+	}
+	return len(categoryOrder)
+}
+
+// compareTimes compares a and b, treating an unknown time as the oldest.
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(*b)
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func groupTestResults() []review.UnresolvedComment {
+	line1, line2 := 10, 3
+	day := func(d int) *time.Time {
+		t := time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	return []review.UnresolvedComment{
+		{Type: "comment", Author: "carol", Body: "c1", Category: "nitpick", CreatedAt: day(1), LastActivityAt: day(1)},
+		{Type: "thread", Path: "b.go", Line: &line1, Author: "alice", Body: "t1", Category: "issue", CreatedAt: day(2), LastActivityAt: day(5)},
+		{Type: "thread", Path: "a.go", Line: &line1, Author: "bob", Body: "t2", Category: "question", CreatedAt: day(3), LastActivityAt: day(3)},
+		{Type: "thread", Path: "a.go", Line: &line2, Author: "alice", Body: "t3", Category: "suggestion", CreatedAt: day(4), LastActivityAt: day(4)},
+	}
+}
+
+func bodies(results []review.UnresolvedComment) []string {
+	var b []string
+	for _, r := range results {
+		b = append(b, r.Body)
+	}
+	return b
+}
+
+func TestSortResults(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"", []string{"c1", "t1", "t2", "t3"}},
+		{"created", []string{"c1", "t1", "t2", "t3"}},
+		{"updated", []string{"t1", "t3", "t2", "c1"}},
+		{"age", []string{"c1", "t2", "t3", "t1"}},
+		{"line", []string{"t3", "t2", "t1", "c1"}},
+		{"category", []string{"t2", "t1", "t3", "c1"}},
+	}
+	for _, tt := range tests {
+		results := groupTestResults()
+		got := bodies(sortResults(results, tt.key))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("sort by %q: got %v, want %v", tt.key, got, tt.want)
+		}
+		if !slices.Equal(bodies(results), []string{"c1", "t1", "t2", "t3"}) {
+			t.Errorf("sort by %q modified the results", tt.key)
+		}
+	}
+}
+
+func TestGroupResults(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"", []string{"b.go: t1", "a.go: t2 t3", "PR Comments: c1"}},
+		{"path", []string{"b.go: t1", "a.go: t2 t3", "PR Comments: c1"}},
+		{"category", []string{"nitpick: c1", "issue: t1", "question: t2", "suggestion: t3"}},
+		{"author", []string{"@carol: c1", "@alice: t1 t3", "@bob: t2"}},
+		{"none", []string{": c1 t1 t2 t3"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, g := range groupResults(groupTestResults(), tt.key) {
+			got = append(got, g.title+": "+strings.Join(bodies(g.comments), " "))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("group by %q: got %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownGroupBy(t *testing.T) {
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, groupTestResults(), newTestOutput(), 80, WithGroupBy("author"), WithSort("category"))
+	out := buf.String()
+	if !strings.HasPrefix(out, "## @bob\n") {
+		t.Errorf("the questions should come first:\n%s", out)
+	}
+	if !strings.Contains(out, "a.go L3\n") {
+		t.Errorf("the path should be in the location line:\n%s", out)
+	}
+	if strings.Contains(out, "## a.go") || strings.Contains(out, "## PR Comments") {
+		t.Errorf("results should not be grouped by path:\n%s", out)
+	}
+
+	buf.Reset()
+	RenderMarkdown(&buf, groupTestResults(), newTestOutput(), 80, WithGroupBy("none"))
+	if out := "\n" + buf.String(); strings.Contains(out, "\n## ") {
+		t.Errorf("there should be no group headers:\n%s", buf.String())
+	}
+}
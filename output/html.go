@@ -0,0 +1,77 @@
+package output
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+//go:embed templates/report.html.tmpl
+var templates embed.FS
+
+var reportTemplate = template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+	"location":  location,
+	"diffLines": diffLines,
+	"plural":    plural,
+}).ParseFS(templates, "templates/report.html.tmpl"))
+
+type htmlGroup struct {
+	Name     string
+	Comments []review.UnresolvedComment
+}
+
+type diffLine struct {
+	Class string
+	Text  string
+}
+
+// RenderHTML writes results as a self-contained HTML report titled title,
+// grouped by file path like RenderMarkdown.
+func RenderHTML(w io.Writer, results []review.UnresolvedComment, title string) error {
+	var categories []string
+	for _, r := range results {
+		if !slices.Contains(categories, r.Category) {
+			categories = append(categories, r.Category)
+		}
+	}
+	var groups []htmlGroup
+	for _, g := range groupResults(results, "path") {
+		groups = append(groups, htmlGroup{Name: g.title, Comments: g.comments})
+	}
+
+	data := struct {
+		Title      string
+		Categories []string
+		Groups     []htmlGroup
+	}{title, categories, groups}
+	if err := reportTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// diffLines splits a diff hunk into lines classified for coloring.
+func diffLines(hunk string) []diffLine {
+	if hunk == "" {
+		return nil
+	}
+	var lines []diffLine
+	for l := range strings.SplitSeq(strings.TrimRight(hunk, "\n"), "\n") {
+		class := "ctx"
+		switch {
+		case strings.HasPrefix(l, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(l, "+"):
+			class = "add"
+		case strings.HasPrefix(l, "-"):
+			class = "del"
+		}
+		lines = append(lines, diffLine{Class: class, Text: l})
+	}
+	return lines
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderHTML(t *testing.T) {
+	line := 42
+	results := []review.UnresolvedComment{
+		{
+			Type:     "thread",
+			Path:     "main.go",
+			Line:     &line,
+			DiffHunk: "@@ -40,2 +40,2 @@\n ctx\n-old\n+new",
+			Author:   "alice",
+			Body:     "Use <b>wrapping</b>",
+			URL:      "https://github.com/o/r/pull/1#discussion_r1",
+			Category: "issue",
+			Reason:   "Not addressed",
+			Replies: []review.Reply{
+				{Author: "bob", Body: "Will do", CreatedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{Type: "comment", Author: "carol", Body: "Tests?", URL: "https://github.com/o/r/pull/1#issuecomment-2", Category: "question"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderHTML(&buf, results, "o/r#1 Add handler"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>o/r#1 Add handler</title>",
+		"<h2>main.go</h2>",
+		"<h2>PR Comments</h2>",
+		`<input type="checkbox" data-category="issue" checked>`,
+		`<input type="checkbox" data-category="question" checked>`,
+		`<span class="hunk">@@ -40,2 &#43;40,2 @@</span>`,
+		`<span class="del">-old</span>`,
+		`<span class="add">&#43;new</span>`,
+		"Use &lt;b&gt;wrapping&lt;/b&gt;",
+		`<a href="https://github.com/o/r/pull/1#discussion_r1">View on GitHub</a>`,
+		"<summary>1 reply</summary>",
+		"<strong>@bob</strong> 2026-01-02 10:00",
+		"L42 | ",
+		"Reason: Not addressed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script src") {
+		t.Error("the report should not load external assets")
+	}
+
+	buf.Reset()
+	if err := RenderHTML(&buf, nil, "empty"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No unresolved comments found.") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
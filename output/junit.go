@@ -0,0 +1,103 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+const prCommentsSuite = "PR Comments"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// RenderJUnit writes results as a JUnit XML report with a test suite per file
+// path (and one for PR comments) and a test case per item. Unresolved items
+// are failures.
+func RenderJUnit(w io.Writer, results []review.UnresolvedComment) error {
+	report := junitTestSuites{Name: "gh-pr-reviews"}
+	suiteIdx := map[string]int{}
+	for _, c := range results {
+		suite := prCommentsSuite
+		if c.Type == "thread" {
+			suite = c.Path
+		}
+		idx, ok := suiteIdx[suite]
+		if !ok {
+			idx = len(report.Suites)
+			suiteIdx[suite] = idx
+			report.Suites = append(report.Suites, junitTestSuite{Name: suite})
+		}
+
+		name := fmt.Sprintf("%s by @%s", c.Category, c.Author)
+		if loc := location(c); loc != "" {
+			name += " at " + loc
+		}
+		tc := junitTestCase{Name: name, ClassName: suite}
+		if !c.Resolved {
+			tc.Failure = &junitFailure{
+				Message: summarize(c.Body),
+				Type:    c.Category,
+				Text:    junitFailureText(c),
+			}
+			report.Suites[idx].Failures++
+			report.Failures++
+		}
+		report.Suites[idx].TestCases = append(report.Suites[idx].TestCases, tc)
+		report.Suites[idx].Tests++
+		report.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitFailureText(c review.UnresolvedComment) string {
+	var b strings.Builder
+	b.WriteString(c.Body)
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "Category: %s\n", c.Category)
+	fmt.Fprintf(&b, "Author: @%s\n", c.Author)
+	if c.URL != "" {
+		fmt.Fprintf(&b, "URL: %s\n", c.URL)
+	}
+	if c.Reason != "" {
+		fmt.Fprintf(&b, "Reason: %s\n", c.Reason)
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	line := 42
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "main.go", Line: &line, Author: "alice", Body: "Handle the error", URL: "https://example.com/1", Category: "issue", Reason: "Not addressed"},
+		{Type: "thread", Path: "main.go", Author: "bob", Body: "LGTM", Category: "approval", Resolved: true},
+		{Type: "thread", Path: "util.go", Author: "bob", Body: "Rename <this>", Category: "nitpick"},
+		{Type: "comment", Author: "carol", Body: "Tests?", Category: "question"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderJUnit(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Errorf("missing XML header:\n%s", out)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Tests != 4 || report.Failures != 3 || len(report.Suites) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	suite := report.Suites[0]
+	if suite.Name != "main.go" || suite.Tests != 2 || suite.Failures != 1 {
+		t.Errorf("unexpected suite: %+v", suite)
+	}
+	tc := suite.TestCases[0]
+	if tc.Name != "issue by @alice at L42" || tc.Failure == nil || tc.Failure.Type != "issue" || tc.Failure.Message != "Handle the error" {
+		t.Errorf("unexpected test case: %+v", tc)
+	}
+	for _, want := range []string{"Author: @alice", "URL: https://example.com/1", "Reason: Not addressed"} {
+		if !strings.Contains(tc.Failure.Text, want) {
+			t.Errorf("missing %q in failure:\n%s", want, tc.Failure.Text)
+		}
+	}
+	if suite.TestCases[1].Failure != nil {
+		t.Error("resolved items should pass")
+	}
+	if report.Suites[2].Name != "PR Comments" {
+		t.Errorf("unexpected suite: %+v", report.Suites[2])
+	}
+	if !strings.Contains(out, "Rename &lt;this&gt;") {
+		t.Errorf("body should be escaped:\n%s", out)
+	}
+}
@@ -1,12 +1,16 @@
 package output
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
 
 	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/muesli/reflow/indent"
 	"github.com/muesli/reflow/wordwrap"
 	"github.com/muesli/termenv"
 	"golang.org/x/term"
@@ -18,6 +22,8 @@ var (
 	colorOrange        = "#F08A3A"
 	colorOrangeBright  = "#FE4C25"
 	colorLink          = "#58A6FF"
+	colorDiffAdd       = "#3FB950"
+	colorDiffDelete    = "#F85149"
 )
 
 const maxWidth = 120
@@ -42,77 +48,107 @@ func DetectWidth(widthFlag int) int {
 	return defaultWidth
 }
 
-// RenderMarkdown writes review results in a colored Markdown-style format.
-func RenderMarkdown(w io.Writer, results []review.UnresolvedComment, p *termenv.Output, width int) {
-	if len(results) == 0 {
-		fmt.Fprintln(w, "No unresolved comments found.")
-		return
+// Option configures RenderMarkdown.
+type Option func(*options)
+
+type options struct {
+	replies     bool
+	lastReplies int
+	explain     bool
+	diffLines   int
+	summary     *review.Stats
+	groupBy     string
+	sortBy      string
+	body        *bodyRenderer
+	replyBody   *bodyRenderer
+}
+
+// WithReplies renders the replies of each thread indented under its first comment.
+// If last > 0, only the last replies are shown.
+func WithReplies(last int) Option {
+	return func(o *options) {
+		o.replies = true
+		o.lastReplies = last
 	}
+}
 
-	// Separate threads (grouped by path) and PR comments.
-	type group struct {
-		path     string
-		comments []review.UnresolvedComment
+// WithExplain renders the classifier input and the raw model response of each item.
+func WithExplain() Option {
+	return func(o *options) {
+		o.explain = true
 	}
-	var groups []group
-	groupIdx := map[string]int{}
-	var prComments []review.UnresolvedComment
+}
 
-	for _, r := range results {
-		if r.Type == "thread" {
-			idx, ok := groupIdx[r.Path]
-			if !ok {
-				idx = len(groups)
-				groupIdx[r.Path] = idx
-				groups = append(groups, group{path: r.Path})
-			}
-			groups[idx].comments = append(groups[idx].comments, r)
-		} else {
-			prComments = append(prComments, r)
-		}
+// WithDiff renders the last n lines of the diff hunk of each thread, which end
+// at the commented line.
+func WithDiff(n int) Option {
+	return func(o *options) {
+		o.diffLines = n
 	}
+}
 
-	first := true
+// WithSummary renders a summary of stats above and below the results.
+func WithSummary(stats review.Stats) Option {
+	return func(o *options) {
+		o.summary = &stats
+	}
+}
 
-	for _, g := range groups {
-		if !first {
-			fmt.Fprintln(w)
+// RenderMarkdown writes review results in a colored Markdown-style format.
+func RenderMarkdown(w io.Writer, results []review.UnresolvedComment, p *termenv.Output, width int, opts ...Option) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No unresolved comments found.")
+		// Still show how many items were resolved.
+		if o.summary != nil {
+			renderSummaryFooter(w, *o.summary, p, width)
 		}
-		first = false
+		return
+	}
+	var err error
+	if o.body, err = newBodyRenderer(p, width); err != nil {
+		slog.Error("rendering comments as plain text", "error", err)
+	}
+	// Replies and draft replies are indented under the comment.
+	if o.replyBody, err = newBodyRenderer(p, max(width-replyIndent, 1)); err != nil {
+		slog.Error("rendering replies as plain text", "error", err)
+	}
 
-		// File path header.
-		header := p.String("## " + g.path).Bold().Foreground(p.Color(colorCopilotPurple))
-		fmt.Fprintln(w, header)
-		fmt.Fprintln(w)
+	if o.summary != nil {
+		renderSummaryHeader(w, *o.summary, p, width)
+	}
 
-		for i, c := range g.comments {
-			renderComment(w, c, p, width)
-			if i < len(g.comments)-1 {
-				fmt.Fprintln(w, p.String("---").Faint())
-				fmt.Fprintln(w)
-			}
+	for i, g := range groupResults(sortResults(results, o.sortBy), o.groupBy) {
+		if i > 0 {
+			fmt.Fprintln(w)
 		}
-	}
 
-	if len(prComments) > 0 {
-		if !first {
+		// Group header.
+		if g.title != "" {
+			header := p.String("## " + g.title).Bold().Foreground(p.Color(colorCopilotPurple))
+			fmt.Fprintln(w, header)
 			fmt.Fprintln(w)
 		}
-		header := p.String("## PR Comments").Bold().Foreground(p.Color(colorCopilotPurple))
-		fmt.Fprintln(w, header)
-		fmt.Fprintln(w)
 
-		for i, c := range prComments {
-			renderComment(w, c, p, width)
-			if i < len(prComments)-1 {
+		for j, c := range g.comments {
+			renderComment(w, c, p, width, o)
+			if j < len(g.comments)-1 {
 				fmt.Fprintln(w, p.String("---").Faint())
 				fmt.Fprintln(w)
 			}
 		}
 	}
+
+	if o.summary != nil {
+		renderSummaryFooter(w, *o.summary, p, width)
+	}
 }
 
-func renderComment(w io.Writer, c review.UnresolvedComment, p *termenv.Output, width int) {
+func renderComment(w io.Writer, c review.UnresolvedComment, p *termenv.Output, width int, o *options) {
 	// Category label.
 	cat := p.String(c.Category).Foreground(p.Color(categoryColor(c.Category)))
 
@@ -129,10 +165,15 @@ func renderComment(w io.Writer, c review.UnresolvedComment, p *termenv.Output, w
 
 	fmt.Fprintf(w, "### %s %s — %s\n\n", cat, status, author)
 
-	// Location line: line number + URL.
+	// Location line: line range + URL.
 	var parts []string
-	if c.Line != nil {
-		parts = append(parts, fmt.Sprintf("L%d", *c.Line))
+	loc := location(c)
+	if o.groupBy != "" && o.groupBy != "path" && c.Path != "" {
+		// The path is not in the group header.
+		loc = strings.TrimSpace(c.Path + " " + loc)
+	}
+	if loc != "" {
+		parts = append(parts, loc)
 	}
 	if c.URL != "" {
 		link := p.String(c.URL).Foreground(p.Color(colorLink)).Underline()
@@ -143,9 +184,143 @@ func renderComment(w io.Writer, c review.UnresolvedComment, p *termenv.Output, w
 		fmt.Fprintln(w)
 	}
 
+	if o.diffLines > 0 {
+		renderDiff(w, c.DiffHunk, p, width, o.diffLines)
+	}
+
 	// Body.
-	fmt.Fprintln(w, wordwrap.String(c.Body, width))
+	fmt.Fprintln(w, o.body.render(c))
+
+	if o.replies {
+		renderReplies(w, c.Replies, p, o.replyBody, o.lastReplies)
+	}
+
+	// Reason.
+	if c.Reason != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, p.String(wordwrap.String("Reason: "+c.Reason, width)).Faint())
+	}
+
+	if c.DraftReply != "" {
+		renderDraftReply(w, c, p, o.replyBody)
+	}
+
+	if o.explain && c.Explanation != nil {
+		renderExplanation(w, c.Explanation, p)
+	}
+}
 
+func renderDiff(w io.Writer, hunk string, p *termenv.Output, width, n int) {
+	lines := review.DiffTail(hunk, n)
+	if len(lines) == 0 {
+		return
+	}
+	for _, l := range lines {
+		l = truncate(l, width)
+		switch {
+		case strings.HasPrefix(l, "+"):
+			fmt.Fprintln(w, p.String(l).Foreground(p.Color(colorDiffAdd)))
+		case strings.HasPrefix(l, "-"):
+			fmt.Fprintln(w, p.String(l).Foreground(p.Color(colorDiffDelete)))
+		default:
+			fmt.Fprintln(w, p.String(l).Faint())
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// truncate shortens s to width runes, marking the cut with an ellipsis.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width < 1 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func renderDraftReply(w io.Writer, c review.UnresolvedComment, p *termenv.Output, b *bodyRenderer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, p.String("Draft reply:").Bold())
+	fmt.Fprintln(w, indent.String(b.renderMarkdown(c.DraftReply), replyIndent))
+	id := c.ThreadID
+	if c.Type != "thread" {
+		id = fmt.Sprintf("%d", c.CommentID)
+	}
+	fmt.Fprintln(w, p.String(fmt.Sprintf("%sPost with: gh pr-reviews reply %s", strings.Repeat(" ", replyIndent), id)).Faint())
+}
+
+func renderExplanation(w io.Writer, e *review.Explanation, p *termenv.Output) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, p.String("Classifier input:").Bold())
+	fmt.Fprintln(w, p.String(indentJSON(e.Input)).Faint())
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, p.String("Model response:").Bold())
+	if len(e.Response) == 0 {
+		fmt.Fprintln(w, p.String("(no entry in the model response)").Faint())
+		return
+	}
+	fmt.Fprintln(w, p.String(indentJSON(e.Response)).Faint())
+}
+
+func indentJSON(b []byte) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		return string(b)
+	}
+	return buf.String()
+}
+
+const replyIndent = 4
+
+func renderReplies(w io.Writer, replies []review.Reply, p *termenv.Output, b *bodyRenderer, last int) {
+	if last > 0 && len(replies) > last {
+		fmt.Fprintln(w)
+		omitted := fmt.Sprintf("%s… %d earlier %s", strings.Repeat(" ", replyIndent), len(replies)-last, plural(len(replies)-last, "reply", "replies"))
+		fmt.Fprintln(w, p.String(omitted).Faint())
+		replies = replies[len(replies)-last:]
+	}
+	for _, r := range replies {
+		fmt.Fprintln(w)
+		author := p.String("@" + r.Author).Bold()
+		date := p.String(r.CreatedAt.Format("2006-01-02 15:04")).Faint()
+		fmt.Fprintf(w, "%s↳ %s %s\n", strings.Repeat(" ", replyIndent), author, date)
+		fmt.Fprintln(w, indent.String(b.renderMarkdown(r.Body), replyIndent))
+	}
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// location returns a label for the lines a comment is attached to, such as
+// "L42", "L40-L48", "L40 (outdated)" or "(file)".
+func location(c review.UnresolvedComment) string {
+	if c.SubjectType == "FILE" {
+		return "(file)"
+	}
+	line, start := c.Line, c.StartLine
+	var notes []string
+	if line == nil && c.OriginalLine != nil {
+		line, start = c.OriginalLine, c.OriginalStartLine
+		notes = append(notes, "outdated")
+	}
+	if line == nil {
+		return ""
+	}
+	if c.DiffSide == "LEFT" {
+		notes = append(notes, "base")
+	}
+	label := fmt.Sprintf("L%d", *line)
+	if start != nil && *start < *line {
+		label = fmt.Sprintf("L%d-L%d", *start, *line)
+	}
+	if len(notes) > 0 {
+		label += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return label
 }
 
 func categoryColor(category string) string {
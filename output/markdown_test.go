@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/k1LoW/gh-pr-reviews/review"
 	"github.com/muesli/termenv"
@@ -246,3 +247,318 @@ func TestRenderMarkdownWordWrap(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderMarkdownLocation(t *testing.T) {
+	start := 40
+	line := 48
+	original := 12
+	tests := []struct {
+		name    string
+		comment review.UnresolvedComment
+		want    string
+	}{
+		{
+			name:    "multi-line range",
+			comment: review.UnresolvedComment{StartLine: &start, Line: &line},
+			want:    "L40-L48 | ",
+		},
+		{
+			name:    "file-level",
+			comment: review.UnresolvedComment{SubjectType: "FILE"},
+			want:    "(file) | ",
+		},
+		{
+			name:    "outdated",
+			comment: review.UnresolvedComment{OriginalLine: &original},
+			want:    "L12 (outdated) | ",
+		},
+		{
+			name:    "base side",
+			comment: review.UnresolvedComment{Line: &line, DiffSide: "LEFT"},
+			want:    "L48 (base) | ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.comment
+			c.Type = "thread"
+			c.Path = "main.go"
+			c.Author = "alice"
+			c.Body = "Comment"
+			c.URL = "https://github.com/example/1"
+			c.Category = "suggestion"
+
+			var buf bytes.Buffer
+			RenderMarkdown(&buf, []review.UnresolvedComment{c}, newTestOutput(), 80)
+			if !strings.Contains(buf.String(), tt.want+c.URL) {
+				t.Errorf("expected location %q in output:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownReplies(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{
+			ThreadID: "t1",
+			Type:     "thread",
+			Path:     "main.go",
+			Author:   "alice",
+			Body:     "Why is this needed?",
+			URL:      "https://github.com/example/1",
+			Category: "question",
+			Replies: []review.Reply{
+				{Author: "bob", Body: "First reply", CreatedAt: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Author: "carol", Body: "Second reply", CreatedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+				{Author: "bob", Body: "Third reply", CreatedAt: time.Date(2026, 1, 3, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, results, newTestOutput(), 80)
+	if strings.Contains(buf.String(), "First reply") {
+		t.Error("replies should not be rendered by default")
+	}
+
+	buf.Reset()
+	RenderMarkdown(&buf, results, newTestOutput(), 80, WithReplies(0))
+	out := buf.String()
+	for _, want := range []string{"    ↳ @bob 2026-01-01 10:00", "    First reply", "    ↳ @carol", "    Third reply"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	RenderMarkdown(&buf, results, newTestOutput(), 80, WithReplies(1))
+	out = buf.String()
+	if strings.Contains(out, "First reply") || strings.Contains(out, "Second reply") {
+		t.Error("only the last reply should be rendered")
+	}
+	if !strings.Contains(out, "… 2 earlier replies") || !strings.Contains(out, "Third reply") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRenderMarkdownReasonAndExplain(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{
+			Type:     "comment",
+			Author:   "alice",
+			Body:     "Please add tests",
+			URL:      "https://github.com/example/1",
+			Category: "suggestion",
+			Reason:   "No follow-up addressing this feedback",
+			Explanation: &review.Explanation{
+				Input:    []byte(`{"id":"PC1","author":"alice"}`),
+				Response: []byte(`{"id":"PC1","category":"suggestion"}`),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, results, newTestOutput(), 80)
+	out := buf.String()
+	if !strings.Contains(out, "Reason: No follow-up addressing this feedback") {
+		t.Errorf("missing reason in output:\n%s", out)
+	}
+	if strings.Contains(out, "Classifier input:") {
+		t.Error("explanation should not be rendered by default")
+	}
+
+	buf.Reset()
+	RenderMarkdown(&buf, results, newTestOutput(), 80, WithExplain())
+	out = buf.String()
+	for _, want := range []string{"Classifier input:", `  "author": "alice"`, "Model response:", `  "category": "suggestion"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMarkdownDraftReply(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{
+			ThreadID:   "PRRT_1",
+			Type:       "thread",
+			Path:       "main.go",
+			Author:     "alice",
+			Body:       "Why is this needed?",
+			Category:   "question",
+			DraftReply: "It keeps the cache warm between requests.",
+		},
+		{
+			CommentID:  42,
+			Type:       "comment",
+			Author:     "bob",
+			Body:       "Is this covered by tests?",
+			Category:   "question",
+			DraftReply: "Yes, see TestCache.",
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, results, newTestOutput(), 80)
+	out := buf.String()
+	for _, want := range []string{
+		"Draft reply:",
+		"    It keeps the cache warm between requests.",
+		"    Post with: gh pr-reviews reply PRRT_1",
+		"    Yes, see TestCache.",
+		"    Post with: gh pr-reviews reply 42",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMarkdownDiff(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{
+			Type:     "thread",
+			Path:     "main.go",
+			Author:   "alice",
+			Body:     "Handle the error",
+			Category: "issue",
+			DiffHunk: "@@ -1,5 +1,5 @@\n package main\n func main() {\n-\tlog.Println(err)\n+\treturn err\n }",
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, results, newTestOutput(), 80)
+	if strings.Contains(buf.String(), "return err") {
+		t.Error("the diff should not be rendered by default")
+	}
+
+	buf.Reset()
+	RenderMarkdown(&buf, results, newTestOutput(), 80, WithDiff(3))
+	out := buf.String()
+	want := "-\tlog.Println(err)\n+\treturn err\n }\n\nHandle the error\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("missing %q in output:\n%s", want, out)
+	}
+	if strings.Contains(out, "func main()") || strings.Contains(out, "@@") {
+		t.Errorf("only the last lines should be rendered:\n%s", out)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("got %q", got)
+	}
+	if got := truncate("abc", 4); got != "abc" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRenderMarkdownBody(t *testing.T) {
+	line := 3
+	long := strings.Repeat("x", 60)
+	results := []review.UnresolvedComment{
+		{
+			Type:     "thread",
+			Path:     "main.go",
+			Line:     &line,
+			Author:   "alice",
+			Body:     "Use **return** here:\n\n```suggestion\n\treturn err\n```\n\n```go\n" + long + "\n```",
+			Category: "suggestion",
+			DiffHunk: "@@ -1,3 +1,3 @@\n package main\n func main() {\n+\tlog.Println(err)",
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, results, newTestOutput(), 40)
+	out := buf.String()
+	if strings.Contains(out, "```") {
+		t.Errorf("the body should be rendered as Markdown:\n%s", out)
+	}
+	for _, want := range []string{"-\tlog.Println(err)", "+\treturn err", long} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestSuggestionsAsDiff(t *testing.T) {
+	body := "Try this:\n```suggestion\nfoo()\nbar()\n```\nThanks"
+	got := suggestionsAsDiff(body, []string{"baz()"})
+	want := "Try this:\n```diff\n-baz()\n+foo()\n+bar()\n```\nThanks"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := suggestionsAsDiff("```suggestion\nfoo()", nil); got != "```suggestion\nfoo()" {
+		t.Errorf("an unclosed block should be kept as is, got %q", got)
+	}
+	// A longer closing fence closes the block, as on GitHub.
+	if got := suggestionsAsDiff("```suggestion\nfoo()\n````", nil); got != "```diff\n+foo()\n````" {
+		t.Errorf("unexpected diff for a longer closing fence: %q", got)
+	}
+}
+
+func TestRenderMarkdownRepliesAsMarkdown(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{
+			ThreadID: "t1",
+			Type:     "thread",
+			Path:     "main.go",
+			Author:   "alice",
+			Body:     "Why is this needed?",
+			Category: "question",
+			Replies: []review.Reply{
+				{Author: "bob", Body: "Like this:\n\n```go\nfoo()\n```", CreatedAt: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+			},
+			DraftReply: "See:\n\n```go\nbar()\n```",
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, results, newTestOutput(), 80, WithReplies(0))
+	out := buf.String()
+	if strings.Contains(out, "```") {
+		t.Errorf("replies should be rendered as Markdown:\n%s", out)
+	}
+	for _, want := range []string{"    Like this:", "foo()", "    See:", "bar()"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMarkdownSummary(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "a.go", Author: "alice", Body: "Fix", Category: "issue"},
+		{Type: "thread", Path: "a.go", Author: "alice", Body: "Why?", Category: "question"},
+		{Type: "thread", Path: "b.go", Author: "bob", Body: "Fix too", Category: "issue"},
+		{Type: "comment", Author: "bob", Body: "Done", Category: "issue", Resolved: true},
+	}
+	stats := review.NewStats(results)
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, review.Unresolved(results), newTestOutput(), 80, WithSummary(stats))
+	out := buf.String()
+	header := "3 unresolved: 2 issue, 1 question — 2 from @alice, 1 from @bob\n\n## a.go\n"
+	if !strings.HasPrefix(out, header) {
+		t.Errorf("output should start with %q:\n%s", header, out)
+	}
+	footer := "## Summary\n\nTotal: 4 (3 unresolved, 1 resolved)\nBy category: issue 2, question 1\nBy author: @alice 2, @bob 1\nBy file: a.go 2, b.go 1\n"
+	if !strings.HasSuffix(out, footer) {
+		t.Errorf("output should end with %q:\n%s", footer, out)
+	}
+}
+
+func TestRenderMarkdownSummaryAllResolved(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "a.go", Author: "alice", Body: "Fix", Category: "issue", Resolved: true},
+		{Type: "comment", Author: "bob", Body: "Done", Category: "issue", Resolved: true},
+	}
+	stats := review.NewStats(results)
+
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, review.Unresolved(results), newTestOutput(), 80, WithSummary(stats))
+	want := "No unresolved comments found.\n\n## Summary\n\nTotal: 2 (0 unresolved, 2 resolved)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
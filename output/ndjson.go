@@ -0,0 +1,19 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RenderNDJSON writes each item as a JSON object on its own line, so that
+// batches of results can be written as soon as they are available.
+func RenderNDJSON[T any](w io.Writer, items []T) error {
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderNDJSON(t *testing.T) {
+	results := []review.UnresolvedComment{
+		{Type: "thread", Path: "main.go", Author: "alice", Body: "a\nb", Category: "issue"},
+		{Type: "comment", Author: "bob", Body: "c", Category: "question"},
+	}
+	var buf bytes.Buffer
+	if err := RenderNDJSON(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"comment_id":0,"type":"thread","path":"main.go","author":"alice","body":"a\nb","url":"","category":"issue","resolved":false,"reason":""}` + "\n" +
+		`{"comment_id":0,"type":"comment","author":"bob","body":"c","url":"","category":"question","resolved":false,"reason":""}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/k1LoW/gh-pr-reviews/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	infoURI      = "https://github.com/k1LoW/gh-pr-reviews"
+)
+
+// categoryDescriptions describe the categories as SARIF rules.
+var categoryDescriptions = map[string]string{
+	"suggestion":    "Code change proposals or improvement requests",
+	"nitpick":       "Minor style/formatting/naming issues",
+	"issue":         "Bug reports or problem identification",
+	"question":      "Questions about the code",
+	"approval":      "Approval comments",
+	"informational": "FYI, context, or background information",
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+type sarifProperties struct {
+	ThreadID   string  `json:"threadId"`
+	URL        string  `json:"url"`
+	Author     string  `json:"author"`
+	Reason     string  `json:"reason,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// RenderSARIF writes the unresolved threads of results as a SARIF 2.1.0 log,
+// with a result per thread whose rule is its category. PR comments have no
+// location in the code and are omitted.
+func RenderSARIF(w io.Writer, results []review.UnresolvedComment) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           version.Name,
+			Version:        version.Version,
+			InformationURI: infoURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	rules := map[string]bool{}
+	for _, c := range review.Unresolved(results) {
+		if c.Type != "thread" {
+			continue
+		}
+		if !rules[c.Category] {
+			rules[c.Category] = true
+			desc, ok := categoryDescriptions[c.Category]
+			if !ok {
+				desc = c.Category
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               c.Category,
+				ShortDescription: sarifMessage{Text: desc},
+			})
+		}
+		loc := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: c.Path, URIBaseID: "%SRCROOT%"},
+		}
+		if start, end, ok := headLines(c); ok {
+			loc.Region = &sarifRegion{StartLine: start, EndLine: end}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    c.Category,
+			Level:     sarifLevel(c.Category),
+			Message:   sarifMessage{Text: c.Body},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+			// Keep the identity of a result stable across runs as its lines move.
+			PartialFingerprints: map[string]string{"reviewThreadId/v1": c.ThreadID},
+			Properties: sarifProperties{
+				ThreadID:   c.ThreadID,
+				URL:        c.URL,
+				Author:     c.Author,
+				Reason:     c.Reason,
+				Confidence: c.Confidence,
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}); err != nil {
+		return fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+	return nil
+}
+
+func sarifLevel(category string) string {
+	switch category {
+	case "issue":
+		return "error"
+	case "suggestion", "question":
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	line, start := 42, 40
+	results := []review.UnresolvedComment{
+		{ThreadID: "T1", Type: "thread", Path: "main.go", Line: &line, StartLine: &start, Author: "alice", Body: "Handle the error", URL: "https://example.com/1", Category: "issue", Reason: "Not addressed"},
+		{ThreadID: "T2", Type: "thread", Path: "old.go", OriginalLine: &line, Author: "bob", Body: "Rename", Category: "nitpick"},
+		{ThreadID: "T3", Type: "thread", Path: "main.go", Line: &line, Author: "bob", Body: "Fix this too", Category: "issue"},
+		{ThreadID: "T4", Type: "thread", Path: "main.go", Line: &line, Author: "bob", Body: "LGTM", Category: "approval", Resolved: true},
+		{Type: "comment", Author: "carol", Body: "Tests?", Category: "question"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderSARIF(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "issue" || run.Tool.Driver.Rules[1].ID != "nitpick" {
+		t.Errorf("unexpected rules: %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(run.Results))
+	}
+
+	r := run.Results[0]
+	if r.RuleID != "issue" || r.Level != "error" || r.Message.Text != "Handle the error" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if region := r.Locations[0].PhysicalLocation.Region; region == nil || region.StartLine != 40 || region.EndLine != 42 {
+		t.Errorf("unexpected region: %+v", region)
+	}
+	if r.Properties.URL != "https://example.com/1" || r.Properties.Reason != "Not addressed" || r.PartialFingerprints["reviewThreadId/v1"] != "T1" {
+		t.Errorf("unexpected properties: %+v", r)
+	}
+
+	outdated := run.Results[1]
+	if outdated.Level != "note" || outdated.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("unexpected outdated result: %+v", outdated)
+	}
+}
+
+func TestRenderSARIFEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderSARIF(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"rules": []`, `"results": []`} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("missing %s in output:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRenderSARIFUnknownCategory(t *testing.T) {
+	line := 1
+	results := []review.UnresolvedComment{{ThreadID: "T1", Type: "thread", Path: "main.go", Line: &line, Body: "?", Category: "unknown"}}
+	var buf bytes.Buffer
+	if err := RenderSARIF(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if rules := log.Runs[0].Tool.Driver.Rules; len(rules) != 1 || rules[0].ShortDescription.Text != "unknown" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+}
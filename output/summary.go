@@ -0,0 +1,71 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/termenv"
+)
+
+// renderSummaryHeader writes a line such as
+// "7 unresolved: 3 issue, 2 question, 2 nitpick — 4 from @alice, 3 from @bob".
+func renderSummaryHeader(w io.Writer, s review.Stats, p *termenv.Output, width int) {
+	line := fmt.Sprintf("%d unresolved", s.Unresolved)
+	if cats := unresolvedCounts(s.ByCategory, "%d %s"); len(cats) > 0 {
+		line += ": " + strings.Join(cats, ", ")
+	}
+	if authors := unresolvedCounts(s.ByAuthor, "%d from @%s"); len(authors) > 0 {
+		line += " — " + strings.Join(authors, ", ")
+	}
+	fmt.Fprintln(w, p.String(wordwrap.String(line, width)).Bold())
+	fmt.Fprintln(w)
+}
+
+// renderSummaryFooter writes the counts of unresolved items by category, author
+// and file, and the total and resolved counts.
+func renderSummaryFooter(w io.Writer, s review.Stats, p *termenv.Output, width int) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, p.String("## Summary").Bold().Foreground(p.Color(colorCopilotPurple)))
+	fmt.Fprintln(w)
+	lines := []string{fmt.Sprintf("Total: %d (%d unresolved, %d resolved)", s.Total, s.Unresolved, s.Resolved)}
+	for _, by := range []struct {
+		label  string
+		counts []string
+	}{
+		{"By category", unresolvedCounts(s.ByCategory, "%[2]s %[1]d")},
+		{"By author", unresolvedCounts(s.ByAuthor, "@%[2]s %[1]d")},
+		{"By file", unresolvedCounts(s.ByFile, "%[2]s %[1]d")},
+	} {
+		if len(by.counts) > 0 {
+			lines = append(lines, by.label+": "+strings.Join(by.counts, ", "))
+		}
+	}
+	for _, l := range lines {
+		fmt.Fprintln(w, wordwrap.String(l, width))
+	}
+}
+
+// unresolvedCounts formats the unresolved count and the key of each entry of m
+// with format, in descending order of the count. Entries without unresolved
+// items are omitted.
+func unresolvedCounts(m map[string]review.CategoryStats, format string) []string {
+	keys := make([]string, 0, len(m))
+	for k, c := range m {
+		if c.Unresolved > 0 {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(m[b].Unresolved, m[a].Unresolved), cmp.Compare(a, b))
+	})
+	counts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		counts = append(counts, fmt.Sprintf(format, m[k].Unresolved, k))
+	}
+	return counts
+}
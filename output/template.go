@@ -0,0 +1,43 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/cli/go-gh/v2/pkg/template"
+)
+
+// RenderTemplate writes v formatted with a Go template, with the same helper
+// functions as gh's --template (tablerow, timeago, color, hyperlink, ...).
+// The template is executed on the JSON representation of v, such as results
+// or results with selected fields.
+func RenderTemplate(w io.Writer, v any, tmpl string, width int, colorEnabled bool) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	t := template.New(w, width, colorEnabled)
+	if err := t.Parse(tmpl); err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := t.Execute(bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return t.Flush()
+}
+
+// RenderJQ writes the result of the jq expression expr evaluated on the JSON
+// representation of v, like gh's --jq.
+func RenderJQ(w io.Writer, v any, expr string, colorize bool) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	if err := jq.EvaluateFormatted(bytes.NewReader(b), w, expr, "  ", colorize); err != nil {
+		return fmt.Errorf("failed to evaluate jq expression: %w", err)
+	}
+	return nil
+}
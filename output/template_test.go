@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/k1LoW/gh-pr-reviews/review"
+)
+
+var templateResults = []review.UnresolvedComment{
+	{Type: "thread", Path: "main.go", Author: "alice", Body: "Handle the error", Category: "issue"},
+	{Type: "comment", Author: "bob", Body: "Tests?", Category: "question"},
+}
+
+func TestRenderTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	tmpl := `{{range .}}{{tablerow .category (printf "@%s" .author) .body}}{{end}}`
+	if err := RenderTemplate(&buf, templateResults, tmpl, 80, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "issue     @alice  Handle the error\nquestion  @bob    Tests?\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+
+	if err := RenderTemplate(&buf, templateResults, "{{range}", 80, false); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestRenderJQ(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderJQ(&buf, templateResults, `.[] | select(.category == "issue") | .author`, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "alice\n" {
+		t.Errorf("got %q", got)
+	}
+
+	buf.Reset()
+	if err := RenderJQ(&buf, templateResults, `map(.type)`, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "[\n  \"thread\",\n  \"comment\"\n]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := RenderJQ(&buf, templateResults, `.[`, false); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+}
@@ -0,0 +1,70 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Files returns the paths changed by a unified diff, in order of appearance.
+func Files(diff string) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for line := range strings.SplitSeq(diff, "\n") {
+		var p string
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			p = line[len("--- "):]
+		case strings.HasPrefix(line, "+++ "):
+			p = line[len("+++ "):]
+		default:
+			continue
+		}
+		// Strip a trailing timestamp as written by diff(1).
+		if i := strings.IndexByte(p, '\t'); i >= 0 {
+			p = p[:i]
+		}
+		if p == "/dev/null" {
+			continue
+		}
+		p = strings.TrimPrefix(strings.TrimPrefix(p, "a/"), "b/")
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// Check reports whether diff applies cleanly to the working tree at dir.
+func Check(dir, diff string) error {
+	return gitApply(dir, diff, "--check")
+}
+
+// ApplyToTree applies diff to the working tree at dir without staging it.
+func ApplyToTree(dir, diff string) error {
+	return gitApply(dir, diff)
+}
+
+// gitApply runs git apply in dir with diff on standard input.
+// Hunk line counts are recounted, as hand-written diffs often get them wrong.
+func gitApply(dir, diff string, args ...string) error {
+	if !strings.HasSuffix(diff, "\n") {
+		diff += "\n"
+	}
+	cmd := exec.Command("git", append(append([]string{"apply", "--recount"}, args...), "-")...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(diff)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("patch does not apply: %s", strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("failed to run git apply: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package patch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-a
++b
+--- /dev/null
++++ b/new.go	2026-01-01 00:00:00
+@@ -0,0 +1 @@
++c
+`
+	got := Files(diff)
+	want := []string{"main.go", "new.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCheckAndApplyToTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	content := "one\ntwo\nthree\n"
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := Unified("a.txt", content, []Edit{{StartLine: 2, EndLine: 2, Lines: []string{"TWO"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wrong hunk counts are tolerated.
+	diff = strings.Replace(diff, "@@ -1,3 +1,3 @@", "@@ -1,5 +1,5 @@", 1)
+	if err := Check(dir, diff); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyToTree(dir, strings.TrimSuffix(diff, "\n")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "one\nTWO\nthree\n" {
+		t.Errorf("got %q", got)
+	}
+
+	// The same diff no longer applies.
+	err = Check(dir, diff)
+	if err == nil || !strings.Contains(err.Error(), "patch does not apply") {
+		t.Errorf("expected a patch error, got %v", err)
+	}
+}
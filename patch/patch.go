@@ -0,0 +1,169 @@
+package patch
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const contextLines = 3
+
+// Edit replaces lines StartLine..EndLine (1-based, inclusive) with Lines.
+type Edit struct {
+	StartLine int
+	EndLine   int
+	Lines     []string
+}
+
+// Apply returns content with edits applied.
+func Apply(content string, edits []Edit) (string, error) {
+	lines, noEOL := split(content)
+	sorted, err := sortEdits(edits, len(lines))
+	if err != nil {
+		return "", err
+	}
+	var out []string
+	next := 1
+	for _, e := range sorted {
+		out = append(out, lines[next-1:e.StartLine-1]...)
+		out = append(out, e.Lines...)
+		next = e.EndLine + 1
+	}
+	out = append(out, lines[next-1:]...)
+	if len(out) == 0 {
+		return "", nil
+	}
+	result := strings.Join(out, "\n")
+	if !noEOL {
+		result += "\n"
+	}
+	return result, nil
+}
+
+// Unified returns a unified diff that applies edits to content of the file at path.
+func Unified(path, content string, edits []Edit) (string, error) {
+	lines, noEOL := split(content)
+	sorted, err := sortEdits(edits, len(lines))
+	if err != nil {
+		return "", err
+	}
+	if len(sorted) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", path, path)
+	fmt.Fprintf(&b, "--- a/%s\n", path)
+	fmt.Fprintf(&b, "+++ b/%s\n", path)
+
+	delta := 0
+	for _, h := range groupHunks(sorted, len(lines)) {
+		var ops []string
+		oldCount, newCount := 0, 0
+		i := h.start
+		for _, e := range h.edits {
+			for ; i < e.StartLine; i++ {
+				ops = append(ops, " "+lines[i-1])
+				oldCount++
+				newCount++
+			}
+			for ; i <= e.EndLine; i++ {
+				ops = append(ops, "-"+lines[i-1])
+				oldCount++
+			}
+			for _, l := range e.Lines {
+				ops = append(ops, "+"+l)
+				newCount++
+			}
+		}
+		for ; i <= h.end; i++ {
+			ops = append(ops, " "+lines[i-1])
+			oldCount++
+			newCount++
+		}
+		if noEOL && h.end == len(lines) {
+			ops = markNoEOL(ops)
+		}
+
+		newStart := h.start + delta
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.start, oldCount, newStart, newCount)
+		for _, op := range ops {
+			b.WriteString(op)
+			b.WriteString("\n")
+		}
+		delta += newCount - oldCount
+	}
+
+	return b.String(), nil
+}
+
+type hunk struct {
+	start int
+	end   int
+	edits []Edit
+}
+
+func groupHunks(edits []Edit, total int) []hunk {
+	var hunks []hunk
+	for _, e := range edits {
+		start := max(1, e.StartLine-contextLines)
+		end := min(total, e.EndLine+contextLines)
+		if n := len(hunks); n > 0 && start <= hunks[n-1].end+1 {
+			hunks[n-1].end = end
+			hunks[n-1].edits = append(hunks[n-1].edits, e)
+			continue
+		}
+		hunks = append(hunks, hunk{start: start, end: end, edits: []Edit{e}})
+	}
+	return hunks
+}
+
+// markNoEOL inserts "\ No newline at end of file" after the last line of
+// each side of the hunk.
+func markNoEOL(ops []string) []string {
+	const marker = `\ No newline at end of file`
+	lastOld, lastNew := -1, -1
+	for i, op := range ops {
+		switch op[0] {
+		case ' ':
+			lastOld, lastNew = i, i
+		case '-':
+			lastOld = i
+		case '+':
+			lastNew = i
+		}
+	}
+	var out []string
+	for i, op := range ops {
+		out = append(out, op)
+		if i == lastOld || i == lastNew {
+			out = append(out, marker)
+		}
+	}
+	return out
+}
+
+func sortEdits(edits []Edit, total int) ([]Edit, error) {
+	sorted := slices.Clone(edits)
+	slices.SortFunc(sorted, func(a, b Edit) int { return a.StartLine - b.StartLine })
+	for i, e := range sorted {
+		if e.StartLine < 1 || e.EndLine < e.StartLine || e.EndLine > total {
+			return nil, fmt.Errorf("edit range L%d-L%d is out of bounds (file has %d lines)", e.StartLine, e.EndLine, total)
+		}
+		if i > 0 && e.StartLine <= sorted[i-1].EndLine {
+			return nil, fmt.Errorf("edit range L%d-L%d overlaps L%d-L%d", e.StartLine, e.EndLine, sorted[i-1].StartLine, sorted[i-1].EndLine)
+		}
+	}
+	return sorted, nil
+}
+
+func split(content string) ([]string, bool) {
+	if content == "" {
+		return nil, false
+	}
+	noEOL := !strings.HasSuffix(content, "\n")
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), noEOL
+}
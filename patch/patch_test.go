@@ -0,0 +1,185 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+)
+
+const testContent = "line1\nline2\nline3\nline4\nline5\nline6\nline7\nline8\nline9\nline10\n"
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edits   []Edit
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "replace single line",
+			content: testContent,
+			edits:   []Edit{{StartLine: 2, EndLine: 2, Lines: []string{"LINE2"}}},
+			want:    "line1\nLINE2\nline3\nline4\nline5\nline6\nline7\nline8\nline9\nline10\n",
+		},
+		{
+			name:    "replace range with more lines and delete",
+			content: testContent,
+			edits: []Edit{
+				{StartLine: 9, EndLine: 10, Lines: nil},
+				{StartLine: 1, EndLine: 2, Lines: []string{"a", "b", "c"}},
+			},
+			want: "a\nb\nc\nline3\nline4\nline5\nline6\nline7\nline8\n",
+		},
+		{
+			name:    "no newline at end of file",
+			content: "a\nb",
+			edits:   []Edit{{StartLine: 2, EndLine: 2, Lines: []string{"B"}}},
+			want:    "a\nB",
+		},
+		{
+			name:    "overlapping",
+			content: testContent,
+			edits: []Edit{
+				{StartLine: 2, EndLine: 4, Lines: []string{"x"}},
+				{StartLine: 4, EndLine: 5, Lines: []string{"y"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "out of bounds",
+			content: testContent,
+			edits:   []Edit{{StartLine: 10, EndLine: 11, Lines: []string{"x"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.content, tt.edits)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edits   []Edit
+		want    string
+	}{
+		{
+			name:    "single hunk",
+			content: testContent,
+			edits:   []Edit{{StartLine: 5, EndLine: 5, Lines: []string{"LINE5", "LINE5b"}}},
+			want: `diff --git a/f.go b/f.go
+--- a/f.go
++++ b/f.go
+@@ -2,7 +2,8 @@
+ line2
+ line3
+ line4
+-line5
++LINE5
++LINE5b
+ line6
+ line7
+ line8
+`,
+		},
+		{
+			name:    "separate hunks shift new start",
+			content: testContent,
+			edits: []Edit{
+				{StartLine: 10, EndLine: 10, Lines: []string{"LINE10"}},
+				{StartLine: 1, EndLine: 1, Lines: nil},
+			},
+			want: `diff --git a/f.go b/f.go
+--- a/f.go
++++ b/f.go
+@@ -1,4 +1,3 @@
+-line1
+ line2
+ line3
+ line4
+@@ -7,4 +6,4 @@
+ line7
+ line8
+ line9
+-line10
++LINE10
+`,
+		},
+		{
+			name:    "adjacent edits merge into one hunk",
+			content: testContent,
+			edits: []Edit{
+				{StartLine: 2, EndLine: 2, Lines: []string{"LINE2"}},
+				{StartLine: 8, EndLine: 8, Lines: []string{"LINE8"}},
+			},
+			want: `diff --git a/f.go b/f.go
+--- a/f.go
++++ b/f.go
+@@ -1,10 +1,10 @@
+ line1
+-line2
++LINE2
+ line3
+ line4
+ line5
+ line6
+ line7
+-line8
++LINE8
+ line9
+ line10
+`,
+		},
+		{
+			name:    "no newline at end of file",
+			content: "a\nb",
+			edits:   []Edit{{StartLine: 2, EndLine: 2, Lines: []string{"B"}}},
+			want: `diff --git a/f.go b/f.go
+--- a/f.go
++++ b/f.go
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++B
+\ No newline at end of file
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unified("f.go", tt.content, tt.edits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedNoEdits(t *testing.T) {
+	got, err := Unified("f.go", testContent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(got) != "" {
+		t.Errorf("expected empty diff, got %q", got)
+	}
+}
@@ -32,20 +32,47 @@ For each comment or thread, determine:
 
 3. **reason**: Brief explanation of your classification and resolution decision.
 
+4. **confidence**: A number from 0.0 to 1.0 expressing how certain you are of the is_resolved decision. Use a high value only when the conversation contains explicit evidence (e.g., the author confirms the fix, or the question is clearly answered).
+
 You will receive a JSON object with "threads" (inline review threads) and "pr_comments" (top-level PR comments).
 
-Return a JSON object (no markdown fences) with the same structure, adding category, is_resolved, and reason fields:
+Return a JSON object (no markdown fences) with the same structure, adding category, is_resolved, reason, and confidence fields:
 {
-  "threads": [{"thread_id": "...", "category": "...", "is_resolved": true/false, "reason": "..."}],
-  "pr_comments": [{"id": "...", "category": "...", "is_resolved": true/false, "reason": "..."}]
+  "threads": [{"thread_id": "...", "category": "...", "is_resolved": true/false, "reason": "...", "confidence": 0.0-1.0}],
+  "pr_comments": [{"id": "...", "category": "...", "is_resolved": true/false, "reason": "...", "confidence": 0.0-1.0}]
 }
 
 Return ONLY valid JSON. Do not wrap in markdown code fences.`
 
+const draftSystemPrompt = `You help the author of a pull request answer a reviewer's question.
+
+You will receive a JSON object with:
+- "comments": the conversation, oldest first. The first comment is the question.
+- "path", "line", "diff_hunk": the location and diff context of the question, if it is an inline review thread.
+- "file_excerpt": the current content of the file around the line, starting at line "file_excerpt_start_line".
+
+Write a concise, factual reply in Markdown from the PR author's point of view that answers the question using only the given context. If the context is not enough to answer, say what needs to be confirmed instead of guessing.
+
+Return ONLY the reply body. Do not wrap it in code fences or add a preamble.`
+
+const fixSystemPrompt = `You write minimal code changes that address pull request review feedback.
+
+You will receive a JSON object with:
+- "comments": the review conversation, oldest first.
+- "path", "start_line", "line", "diff_hunk": the location and diff context the feedback is attached to.
+- "file_content": the current content of the file at "path".
+
+Write the smallest change to the file that addresses the feedback. Do not reformat or touch unrelated code.
+
+Return ONLY a unified diff against "file_content" with "--- a/<path>" and "+++ b/<path>" headers, "@@" hunk headers and 3 lines of context. Context and removed lines must match "file_content" exactly, including whitespace. Do not wrap the diff in code fences or add any explanation.`
+
 // CopilotClassifier uses the Copilot SDK to classify review comments.
 type CopilotClassifier struct {
-	client  *copilot.Client
-	session *copilot.Session
+	client *copilot.Client
+	model  string
+	// send sends a prompt to a new session, so that no request sees the
+	// history of another. It is replaced in tests.
+	send func(ctx context.Context, systemPrompt, prompt string) (string, error)
 }
 
 // NewCopilotClassifier creates a new CopilotClassifier.
@@ -62,44 +89,106 @@ func NewCopilotClassifier(ctx context.Context, model string) (*CopilotClassifier
 		return nil, fmt.Errorf("failed to start copilot client: %w", err)
 	}
 
-	session, err := client.CreateSession(ctx, &copilot.SessionConfig{
-		Model: model,
-		SystemMessage: &copilot.SystemMessageConfig{
-			Content: systemPrompt,
-		},
-	})
-	if err != nil {
-		client.Stop() //nolint:errcheck
-		return nil, fmt.Errorf("failed to create copilot session: %w", err)
+	c := &CopilotClassifier{
+		client: client,
+		model:  model,
 	}
-
-	return &CopilotClassifier{
-		client:  client,
-		session: session,
-	}, nil
+	c.send = c.ask
+	return c, nil
 }
 
 // ClassifyAll sends all review data to Copilot and returns classification results.
+// Each call, such as for a batch of Analyze, is classified in a new session,
+// so that the context does not grow with earlier batches.
 func (c *CopilotClassifier) ClassifyAll(ctx context.Context, input *ClassifyInput) (*ClassifyOutput, error) {
 	inputJSON, err := json.Marshal(input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal classify input: %w", err)
 	}
 
+	responseContent, err := c.send(ctx, systemPrompt, string(inputJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := parseClassifyOutput(responseContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse copilot response: %w", err)
+	}
+
+	return output, nil
+}
+
+// DraftReply asks Copilot to draft an answer to the question in input.
+// Each question is asked in a new session, so that earlier questions and
+// file excerpts do not leak into the answer.
+func (c *CopilotClassifier) DraftReply(ctx context.Context, input *DraftInput) (string, error) {
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal draft input: %w", err)
+	}
+
+	responseContent, err := c.send(ctx, draftSystemPrompt, string(inputJSON))
+	if err != nil {
+		return "", err
+	}
+	return stripWrappingFence(responseContent), nil
+}
+
+// ProposeFix asks Copilot for a unified diff that addresses the feedback in input.
+// Each fix is requested in a new session, so that earlier files and diffs do
+// not leak into the patch.
+func (c *CopilotClassifier) ProposeFix(ctx context.Context, input *FixInput) (string, error) {
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fix input: %w", err)
+	}
+
+	responseContent, err := c.send(ctx, fixSystemPrompt, string(inputJSON))
+	if err != nil {
+		return "", err
+	}
+	return stripCodeFence(responseContent), nil
+}
+
+// Close shuts down the Copilot client.
+func (c *CopilotClassifier) Close() {
+	if c.client != nil {
+		c.client.Stop() //nolint:errcheck
+	}
+}
+
+// ask sends prompt to a new session with systemPrompt, and destroys the
+// session once it has answered.
+func (c *CopilotClassifier) ask(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	session, err := c.client.CreateSession(ctx, &copilot.SessionConfig{
+		Model: c.model,
+		SystemMessage: &copilot.SystemMessageConfig{
+			Content: systemPrompt,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create copilot session: %w", err)
+	}
+	defer session.Destroy() //nolint:errcheck
+	return sendAndWait(ctx, session, prompt)
+}
+
+// sendAndWait sends prompt to session and returns the last assistant message
+// once the session becomes idle.
+func sendAndWait(ctx context.Context, session *copilot.Session, prompt string) (string, error) {
 	var responseContent string
 	done := make(chan struct{})
 	var eventErr error
 
-	unsubscribe := c.session.On(func(event copilot.SessionEvent) {
+	unsubscribe := session.On(func(event copilot.SessionEvent) {
 		switch event.Type {
 		case "assistant.message":
 			if event.Data.Content != nil {
 				responseContent = *event.Data.Content
 			}
-		case "session.idle":
-			close(done)
-		case "error":
-			if event.Data.Content != nil {
+		case "session.idle", "error":
+			if event.Type == "error" && event.Data.Content != nil {
 				eventErr = fmt.Errorf("copilot error: %s", *event.Data.Content)
 			}
 			select {
@@ -111,39 +200,23 @@ func (c *CopilotClassifier) ClassifyAll(ctx context.Context, input *ClassifyInpu
 	})
 	defer unsubscribe()
 
-	_, err = c.session.Send(ctx, copilot.MessageOptions{
-		Prompt: string(inputJSON),
+	_, err := session.Send(ctx, copilot.MessageOptions{
+		Prompt: prompt,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send message to copilot: %w", err)
+		return "", fmt.Errorf("failed to send message to copilot: %w", err)
 	}
 
 	select {
 	case <-done:
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return "", ctx.Err()
 	}
 
 	if eventErr != nil {
-		return nil, eventErr
-	}
-
-	output, err := parseClassifyOutput(responseContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse copilot response: %w", err)
-	}
-
-	return output, nil
-}
-
-// Close shuts down the Copilot session and client.
-func (c *CopilotClassifier) Close() {
-	if c.session != nil {
-		c.session.Destroy() //nolint:errcheck
-	}
-	if c.client != nil {
-		c.client.Stop() //nolint:errcheck
+		return "", eventErr
 	}
+	return responseContent, nil
 }
 
 func checkCopilotCLI() error {
@@ -195,8 +268,32 @@ func compareVersions(a, b string) int {
 }
 
 func parseClassifyOutput(raw string) (*ClassifyOutput, error) {
+	raw = stripCodeFence(raw)
+
+	var output ClassifyOutput
+	if err := json.Unmarshal([]byte(raw), &output); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal classify output: %w (raw: %s)", err, truncate(raw, 200))
+	}
+
+	// Keep the raw fragment of each entry for --explain.
+	var fragments struct {
+		Threads    []json.RawMessage `json:"threads"`
+		PRComments []json.RawMessage `json:"pr_comments"`
+	}
+	if err := json.Unmarshal([]byte(raw), &fragments); err == nil {
+		for i := range min(len(output.Threads), len(fragments.Threads)) {
+			output.Threads[i].Raw = fragments.Threads[i]
+		}
+		for i := range min(len(output.PRComments), len(fragments.PRComments)) {
+			output.PRComments[i].Raw = fragments.PRComments[i]
+		}
+	}
+	return &output, nil
+}
+
+// stripCodeFence defensively strips a markdown code fence wrapping the whole response.
+func stripCodeFence(raw string) string {
 	raw = strings.TrimSpace(raw)
-	// Defensively strip markdown code fences.
 	if strings.HasPrefix(raw, "```") {
 		lines := strings.SplitN(raw, "\n", 2)
 		if len(lines) > 1 {
@@ -207,12 +304,24 @@ func parseClassifyOutput(raw string) (*ClassifyOutput, error) {
 		}
 		raw = strings.TrimSpace(raw)
 	}
+	return raw
+}
 
-	var output ClassifyOutput
-	if err := json.Unmarshal([]byte(raw), &output); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal classify output: %w (raw: %s)", err, truncate(raw, 200))
+// stripWrappingFence strips a code fence only if it wraps the whole response,
+// so that a Markdown reply starting or ending with a code block is kept intact.
+func stripWrappingFence(raw string) string {
+	raw = strings.TrimSpace(raw)
+	lines := strings.Split(raw, "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "```") || strings.TrimSpace(lines[len(lines)-1]) != "```" {
+		return raw
 	}
-	return &output, nil
+	inner := lines[1 : len(lines)-1]
+	for _, l := range inner {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			return raw
+		}
+	}
+	return strings.TrimSpace(strings.Join(inner, "\n"))
 }
 
 // ListCopilotModels returns available model IDs from the Copilot SDK.
@@ -1,6 +1,8 @@
 package review
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 )
 
@@ -25,6 +27,9 @@ func TestParseClassifyOutput(t *testing.T) {
 				if o.Threads[0].IsResolved {
 					t.Error("expected IsResolved to be false")
 				}
+				if want := `{"thread_id":"T1","category":"suggestion","is_resolved":false,"reason":"not fixed"}`; string(o.Threads[0].Raw) != want {
+					t.Errorf("expected raw fragment %s, got %s", want, o.Threads[0].Raw)
+				}
 			},
 		},
 		{
@@ -126,3 +131,69 @@ func TestTruncate(t *testing.T) {
 		t.Errorf("expected hello..., got %s", got)
 	}
 }
+
+func TestCopilotClassifierBatches(t *testing.T) {
+	var inputs []ClassifyInput
+	c := &CopilotClassifier{}
+	// Each call of send stands for a new session.
+	c.send = func(_ context.Context, system, prompt string) (string, error) {
+		if system != systemPrompt {
+			t.Errorf("unexpected system prompt: %s", system)
+		}
+		var in ClassifyInput
+		if err := json.Unmarshal([]byte(prompt), &in); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, in)
+		var out ClassifyOutput
+		for _, th := range in.Threads {
+			out.Threads = append(out.Threads, ClassifyOutputThread{ThreadID: th.ThreadID, Category: "issue"})
+		}
+		b, err := json.Marshal(out)
+		return string(b), err
+	}
+	data := &Data{
+		Threads: []Thread{
+			{ID: "T1", Comments: []Comment{{Body: "a"}}},
+			{ID: "T2", Comments: []Comment{{Body: "b"}}},
+			{ID: "T3", Comments: []Comment{{Body: "c"}}},
+		},
+	}
+
+	results, err := Analyze(context.Background(), data, c, false, WithBatchSize(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected 3 results, got %d", len(results))
+	}
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 sessions, got %d", len(inputs))
+	}
+	for i, in := range inputs {
+		if len(in.Threads) != 1 || in.Threads[0].ThreadID != data.Threads[i].ID {
+			t.Errorf("session %d should only see its own batch, got %+v", i, in.Threads)
+		}
+	}
+}
+
+func TestStripWrappingFence(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"plain", "We call f here.", "We call f here."},
+		{"wrapped", "```markdown\nWe call f here.\n```\n", "We call f here."},
+		{"leading code block", "```go\nx := f()\n```\nWe call f here because it is cached.", "```go\nx := f()\n```\nWe call f here because it is cached."},
+		{"trailing code block", "Like this:\n```go\nx := f()\n```", "Like this:\n```go\nx := f()\n```"},
+		{"two code blocks", "```go\nx := f()\n```\nor\n```go\ny := g()\n```", "```go\nx := f()\n```\nor\n```go\ny := g()\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripWrappingFence(tt.raw); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
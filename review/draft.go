@@ -0,0 +1,128 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// excerptRadius is the number of lines included before and after the commented line in drafts.
+const excerptRadius = 50
+
+// DraftInput is the context sent to a ReplyDrafter for an unanswered question.
+type DraftInput struct {
+	Path             string                 `json:"path,omitempty"`
+	Line             *int                   `json:"line,omitempty"`
+	DiffHunk         string                 `json:"diff_hunk,omitempty"`
+	FileExcerpt      string                 `json:"file_excerpt,omitempty"`
+	FileExcerptStart int                    `json:"file_excerpt_start_line,omitempty"`
+	Comments         []ClassifyInputComment `json:"comments"`
+}
+
+// ReplyDrafter drafts replies to review comments.
+type ReplyDrafter interface {
+	DraftReply(ctx context.Context, input *DraftInput) (string, error)
+}
+
+// WithReplyDrafter drafts a reply for each unresolved question using d.
+func WithReplyDrafter(d ReplyDrafter) Option {
+	return func(o *options) {
+		o.drafter = d
+	}
+}
+
+// draftReplies drafts replies for unresolved questions, keyed by thread ID or PR comment ID.
+func draftReplies(ctx context.Context, data *Data, output *ClassifyOutput, drafter ReplyDrafter) (map[string]string, error) {
+	drafts := map[string]string{}
+
+	questions := map[string]bool{}
+	for _, t := range output.Threads {
+		if t.Category == "question" && !t.IsResolved {
+			questions[t.ThreadID] = true
+		}
+	}
+	for _, c := range output.PRComments {
+		if c.Category == "question" && !c.IsResolved {
+			questions[c.ID] = true
+		}
+	}
+
+	for _, t := range data.Threads {
+		if t.IsResolved || !questions[t.ID] {
+			continue
+		}
+		input := &DraftInput{
+			Path:     t.Path,
+			Line:     t.Line,
+			Comments: draftComments(t.Comments),
+		}
+		if len(t.Comments) > 0 {
+			input.DiffHunk = t.Comments[0].DiffHunk
+		}
+		if content, ok := data.Files[t.Path]; ok {
+			input.FileExcerpt, input.FileExcerptStart = excerpt(content, t.Line, excerptRadius)
+		}
+		draft, err := drafter.DraftReply(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to draft a reply for thread %s: %w", t.ID, err)
+		}
+		drafts[t.ID] = draft
+	}
+
+	for _, c := range data.PRComments {
+		if !questions[c.ID] {
+			continue
+		}
+		draft, err := drafter.DraftReply(ctx, &DraftInput{Comments: draftComments([]Comment{c})})
+		if err != nil {
+			return nil, fmt.Errorf("failed to draft a reply for comment %s: %w", c.ID, err)
+		}
+		drafts[c.ID] = draft
+	}
+
+	return drafts, nil
+}
+
+func draftComments(comments []Comment) []ClassifyInputComment {
+	var out []ClassifyInputComment
+	for _, c := range comments {
+		out = append(out, ClassifyInputComment{
+			Author:    c.Author,
+			Body:      c.Body,
+			CreatedAt: c.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return out
+}
+
+// excerpt returns the lines of content within radius of line and the number
+// of the first returned line. Without a line, the beginning of the file is returned.
+func excerpt(content string, line *int, radius int) (string, int) {
+	lines := splitLines(content)
+	center := 1
+	if line != nil {
+		center = *line
+	}
+	start := max(1, center-radius)
+	end := min(len(lines), center+radius)
+	if start > end {
+		return "", 0
+	}
+	return strings.Join(lines[start-1:end], "\n"), start
+}
+
+// UnresolvedPaths returns the paths of threads that are not resolved on GitHub,
+// whose head contents give context for drafting replies.
+func UnresolvedPaths(data *Data) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, t := range data.Threads {
+		if t.IsResolved || t.Path == "" || seen[t.Path] {
+			continue
+		}
+		seen[t.Path] = true
+		paths = append(paths, t.Path)
+	}
+	return paths
+}
@@ -0,0 +1,115 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockDrafter struct {
+	inputs []*DraftInput
+}
+
+func (m *mockDrafter) DraftReply(_ context.Context, input *DraftInput) (string, error) {
+	m.inputs = append(m.inputs, input)
+	return "Draft for " + input.Comments[0].Body, nil
+}
+
+func TestAnalyzeWithReplyDrafter(t *testing.T) {
+	line := 3
+	data := &Data{
+		Threads: []Thread{
+			{
+				ID:   "T1",
+				Path: "main.go",
+				Line: &line,
+				Comments: []Comment{
+					{ID: "C1", Body: "Why is this needed?", Author: "alice", CreatedAt: time.Now(), DiffHunk: "@@ -1,3 +1,3 @@"},
+				},
+			},
+			{
+				ID:   "T2",
+				Path: "main.go",
+				Comments: []Comment{
+					{ID: "C2", Body: "Please rename this", Author: "alice", CreatedAt: time.Now()},
+				},
+			},
+			{
+				ID:   "T3",
+				Path: "main.go",
+				Comments: []Comment{
+					{ID: "C3", Body: "Is this safe?", Author: "bob", CreatedAt: time.Now()},
+					{ID: "C4", Body: "Yes, it is guarded by the mutex.", Author: "carol", CreatedAt: time.Now()},
+				},
+			},
+		},
+		PRComments: []Comment{
+			{ID: "PC1", Body: "Is this covered by tests?", Author: "dave", CreatedAt: time.Now()},
+		},
+		Files: map[string]string{
+			"main.go": "package main\n\nfunc main() {\n}\n",
+		},
+	}
+	mock := &mockClassifier{
+		output: &ClassifyOutput{
+			Threads: []ClassifyOutputThread{
+				{ThreadID: "T1", Category: "question", IsResolved: false},
+				{ThreadID: "T2", Category: "suggestion", IsResolved: false},
+				{ThreadID: "T3", Category: "question", IsResolved: true},
+			},
+			PRComments: []ClassifyOutputPRComment{
+				{ID: "PC1", Category: "question", IsResolved: false},
+			},
+		},
+	}
+	drafter := &mockDrafter{}
+
+	results, err := Analyze(context.Background(), data, mock, true, WithReplyDrafter(drafter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafter.inputs) != 2 {
+		t.Fatalf("expected 2 drafts, got %d", len(drafter.inputs))
+	}
+
+	drafts := map[string]string{}
+	for _, r := range results {
+		drafts[r.ThreadID+r.Body] = r.DraftReply
+	}
+	if got := drafts["T1Why is this needed?"]; got != "Draft for Why is this needed?" {
+		t.Errorf("unexpected draft for T1: %q", got)
+	}
+	if got := drafts["Is this covered by tests?"]; got != "Draft for Is this covered by tests?" {
+		t.Errorf("unexpected draft for PC1: %q", got)
+	}
+	if drafts["T2Please rename this"] != "" || drafts["T3Is this safe?"] != "" {
+		t.Error("only unresolved questions should get drafts")
+	}
+
+	in := drafter.inputs[0]
+	if in.Path != "main.go" || in.DiffHunk != "@@ -1,3 +1,3 @@" {
+		t.Errorf("unexpected draft input: %+v", in)
+	}
+	if in.FileExcerptStart != 1 || !strings.Contains(in.FileExcerpt, "func main()") {
+		t.Errorf("unexpected file excerpt: %d %q", in.FileExcerptStart, in.FileExcerpt)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	content := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	line := 5
+	got, start := excerpt(content, &line, 2)
+	if got != "3\n4\n5\n6\n7" || start != 3 {
+		t.Errorf("got %q from %d", got, start)
+	}
+	line = 10
+	got, start = excerpt(content, &line, 2)
+	if got != "8\n9\n10" || start != 8 {
+		t.Errorf("got %q from %d", got, start)
+	}
+	got, start = excerpt(content, nil, 1)
+	if got != "1\n2" || start != 1 {
+		t.Errorf("got %q from %d", got, start)
+	}
+}
@@ -0,0 +1,80 @@
+package review
+
+import "time"
+
+// EnvelopeVersion is the version of the Envelope format.
+const EnvelopeVersion = 1
+
+// Envelope wraps the JSON output with the metadata of the pull request and the run.
+type Envelope struct {
+	SchemaVersion int            `json:"schema_version"`
+	PR            PRInfo         `json:"pr"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+	ToolVersion   string         `json:"tool_version"`
+	Classifier    ClassifierInfo `json:"classifier"`
+	Stats         Stats          `json:"stats"`
+	Items         any            `json:"items"` // results, or results with selected fields
+}
+
+// PRInfo identifies the pull request the results are for.
+type PRInfo struct {
+	Owner   string `json:"owner"`
+	Repo    string `json:"repo"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HeadSHA string `json:"head_sha"`
+}
+
+// ClassifierInfo identifies the classifier that produced the results.
+type ClassifierInfo struct {
+	Backend string `json:"backend"`
+	Model   string `json:"model"`
+}
+
+// Stats counts results by status, and by category, author and file.
+type Stats struct {
+	Total      int                      `json:"total"`
+	Resolved   int                      `json:"resolved"`
+	Unresolved int                      `json:"unresolved"`
+	ByCategory map[string]CategoryStats `json:"by_category"`
+	ByAuthor   map[string]CategoryStats `json:"by_author"`
+	ByFile     map[string]CategoryStats `json:"by_file"` // threads only
+}
+
+// CategoryStats counts the results of a category, an author or a file by status.
+type CategoryStats struct {
+	Resolved   int `json:"resolved"`
+	Unresolved int `json:"unresolved"`
+}
+
+// NewStats counts results by status, and by category, author and file.
+func NewStats(results []UnresolvedComment) Stats {
+	s := Stats{
+		ByCategory: map[string]CategoryStats{},
+		ByAuthor:   map[string]CategoryStats{},
+		ByFile:     map[string]CategoryStats{},
+	}
+	for _, r := range results {
+		s.Total++
+		if r.Resolved {
+			s.Resolved++
+		} else {
+			s.Unresolved++
+		}
+		s.ByCategory[r.Category] = s.ByCategory[r.Category].add(r.Resolved)
+		s.ByAuthor[r.Author] = s.ByAuthor[r.Author].add(r.Resolved)
+		if r.Path != "" {
+			s.ByFile[r.Path] = s.ByFile[r.Path].add(r.Resolved)
+		}
+	}
+	return s
+}
+
+func (c CategoryStats) add(resolved bool) CategoryStats {
+	if resolved {
+		c.Resolved++
+	} else {
+		c.Unresolved++
+	}
+	return c
+}
@@ -0,0 +1,68 @@
+package review
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Schema is the JSON Schema of the JSON output, an array of UnresolvedComment.
+//
+//go:embed schema.json
+var Schema []byte
+
+// JSONFields returns the JSON field names of UnresolvedComment in declaration order.
+func JSONFields() []string {
+	t := reflect.TypeFor[UnresolvedComment]()
+	fields := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// ValidateFields returns an error listing the available fields if any of fields is unknown.
+func ValidateFields(fields []string) error {
+	available := JSONFields()
+	for _, f := range fields {
+		if !slices.Contains(available, f) {
+			return fmt.Errorf("unknown JSON field: %q\nAvailable fields:\n  %s", f, strings.Join(available, "\n  "))
+		}
+	}
+	return nil
+}
+
+// SelectFields returns results with only the given JSON fields. Fields that
+// are omitted from an item because they are empty are set to null.
+func SelectFields(results []UnresolvedComment, fields []string) ([]map[string]any, error) {
+	if err := ValidateFields(fields); err != nil {
+		return nil, err
+	}
+	selected := make([]map[string]any, 0, len(results))
+	for _, r := range results {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result: %w", err)
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(b, &all); err != nil {
+			return nil, fmt.Errorf("failed to decode result: %w", err)
+		}
+		m := make(map[string]any, len(fields))
+		for _, f := range fields {
+			if v, ok := all[f]; ok {
+				m[f] = v
+			} else {
+				m[f] = nil
+			}
+		}
+		selected = append(selected, m)
+	}
+	return selected, nil
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchemaMatchesJSONFields(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Required   []string                   `json:"required"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatal(err)
+	}
+	def, ok := schema.Defs["UnresolvedComment"]
+	if !ok {
+		t.Fatal("missing UnresolvedComment definition")
+	}
+	var props []string
+	for p := range def.Properties {
+		props = append(props, p)
+	}
+	slices.Sort(props)
+	fields := JSONFields()
+	slices.Sort(fields)
+	if !slices.Equal(props, fields) {
+		t.Errorf("schema properties %v do not match the JSON fields %v", props, fields)
+	}
+	for _, r := range def.Required {
+		if !slices.Contains(fields, r) {
+			t.Errorf("required property %q is not a JSON field", r)
+		}
+	}
+
+	env, ok := schema.Defs["Envelope"]
+	if !ok {
+		t.Fatal("missing Envelope definition")
+	}
+	props = props[:0]
+	for p := range env.Properties {
+		props = append(props, p)
+	}
+	slices.Sort(props)
+	var envFields []string
+	et := reflect.TypeFor[Envelope]()
+	for i := range et.NumField() {
+		name, _, _ := strings.Cut(et.Field(i).Tag.Get("json"), ",")
+		envFields = append(envFields, name)
+	}
+	slices.Sort(envFields)
+	if !slices.Equal(props, envFields) {
+		t.Errorf("schema properties %v do not match the envelope fields %v", props, envFields)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	line := 42
+	results := []UnresolvedComment{
+		{Type: "thread", Path: "main.go", Line: &line, Category: "issue"},
+		{Type: "comment", Category: "question"},
+	}
+	got, err := SelectFields(results, []string{"path", "line", "category"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"category":"issue","line":42,"path":"main.go"},{"category":"question","line":null,"path":null}]`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	_, err = SelectFields(results, []string{"path", "lines"})
+	if err == nil || !strings.Contains(err.Error(), `unknown JSON field: "lines"`) || !strings.Contains(err.Error(), "  start_line") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewStats(t *testing.T) {
+	results := []UnresolvedComment{
+		{Category: "issue", Author: "alice", Path: "a.go"},
+		{Category: "issue", Author: "bob", Path: "a.go", Resolved: true},
+		{Category: "nitpick", Author: "alice", Path: "b.go"},
+		{Category: "approval", Author: "bob", Resolved: true},
+	}
+	got := NewStats(results)
+	if got.Total != 4 || got.Resolved != 2 || got.Unresolved != 2 {
+		t.Errorf("unexpected stats: %+v", got)
+	}
+	if got.ByCategory["issue"] != (CategoryStats{Resolved: 1, Unresolved: 1}) || got.ByCategory["nitpick"] != (CategoryStats{Unresolved: 1}) {
+		t.Errorf("unexpected category stats: %+v", got.ByCategory)
+	}
+	if got.ByAuthor["alice"] != (CategoryStats{Unresolved: 2}) || got.ByAuthor["bob"] != (CategoryStats{Resolved: 2}) {
+		t.Errorf("unexpected author stats: %+v", got.ByAuthor)
+	}
+	if len(got.ByFile) != 2 || got.ByFile["a.go"] != (CategoryStats{Resolved: 1, Unresolved: 1}) {
+		t.Errorf("unexpected file stats: %+v", got.ByFile)
+	}
+}
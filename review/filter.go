@@ -0,0 +1,31 @@
+package review
+
+import "path"
+
+// ThreadFilter selects review threads by their attributes. Empty fields match any thread.
+type ThreadFilter struct {
+	Author   string // login of the author of the first comment
+	Path     string // glob pattern matched against the file path
+	Outdated bool   // only threads whose diff is outdated
+}
+
+// IsZero reports whether the filter has no conditions.
+func (f ThreadFilter) IsZero() bool {
+	return f == ThreadFilter{}
+}
+
+// Match reports whether t satisfies all conditions of the filter.
+func (f ThreadFilter) Match(t Thread) bool {
+	if f.Author != "" && (len(t.Comments) == 0 || t.Comments[0].Author != f.Author) {
+		return false
+	}
+	if f.Path != "" {
+		if ok, err := path.Match(f.Path, t.Path); err != nil || !ok {
+			return false
+		}
+	}
+	if f.Outdated && !t.IsOutdated {
+		return false
+	}
+	return true
+}
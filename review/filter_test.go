@@ -0,0 +1,38 @@
+package review
+
+import "testing"
+
+func TestThreadFilterMatch(t *testing.T) {
+	thread := Thread{
+		ID:         "T1",
+		IsOutdated: true,
+		Path:       "cmd/root.go",
+		Comments:   []Comment{{Author: "alice"}, {Author: "bob"}},
+	}
+	tests := []struct {
+		name   string
+		filter ThreadFilter
+		want   bool
+	}{
+		{"zero", ThreadFilter{}, true},
+		{"author", ThreadFilter{Author: "alice"}, true},
+		{"reply author", ThreadFilter{Author: "bob"}, false},
+		{"path glob", ThreadFilter{Path: "cmd/*.go"}, true},
+		{"path mismatch", ThreadFilter{Path: "review/*"}, false},
+		{"outdated", ThreadFilter{Outdated: true}, true},
+		{"all", ThreadFilter{Author: "alice", Path: "cmd/root.go", Outdated: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(thread); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	current := thread
+	current.IsOutdated = false
+	if (ThreadFilter{Outdated: true}).Match(current) {
+		t.Error("expected outdated filter not to match a current thread")
+	}
+}
@@ -0,0 +1,45 @@
+package review
+
+import "slices"
+
+// fixCategories are the categories of feedback that ask for a code change.
+var fixCategories = []string{"suggestion", "issue", "nitpick"}
+
+// FixInput is the context sent to the model to propose a fix for an unresolved thread.
+type FixInput struct {
+	Path        string                 `json:"path"`
+	Line        *int                   `json:"line,omitempty"`
+	StartLine   *int                   `json:"start_line,omitempty"`
+	DiffHunk    string                 `json:"diff_hunk,omitempty"`
+	FileContent string                 `json:"file_content"`
+	Comments    []ClassifyInputComment `json:"comments"`
+}
+
+// FixTargets returns the unresolved threads whose feedback asks for a code change.
+func FixTargets(results []UnresolvedComment) []UnresolvedComment {
+	var targets []UnresolvedComment
+	for _, r := range results {
+		if r.Type != "thread" || r.Resolved || r.Path == "" {
+			continue
+		}
+		if slices.Contains(fixCategories, r.Category) {
+			targets = append(targets, r)
+		}
+	}
+	return targets
+}
+
+// NewFixInput builds the input for proposing a fix to t against content.
+func NewFixInput(t Thread, content string) *FixInput {
+	input := &FixInput{
+		Path:        t.Path,
+		Line:        t.Line,
+		StartLine:   t.StartLine,
+		FileContent: content,
+		Comments:    draftComments(t.Comments),
+	}
+	if len(t.Comments) > 0 {
+		input.DiffHunk = t.Comments[0].DiffHunk
+	}
+	return input
+}
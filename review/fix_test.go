@@ -0,0 +1,41 @@
+package review
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixTargets(t *testing.T) {
+	results := []UnresolvedComment{
+		{ThreadID: "T1", Type: "thread", Path: "a.go", Category: "suggestion"},
+		{ThreadID: "T2", Type: "thread", Path: "a.go", Category: "question"},
+		{ThreadID: "T3", Type: "thread", Path: "b.go", Category: "issue", Resolved: true},
+		{ThreadID: "T4", Type: "thread", Path: "b.go", Category: "nitpick"},
+		{Type: "comment", Category: "issue"},
+	}
+	got := FixTargets(results)
+	if len(got) != 2 || got[0].ThreadID != "T1" || got[1].ThreadID != "T4" {
+		t.Errorf("unexpected targets: %+v", got)
+	}
+}
+
+func TestNewFixInput(t *testing.T) {
+	line, start := 12, 10
+	thread := Thread{
+		ID:        "T1",
+		Path:      "main.go",
+		Line:      &line,
+		StartLine: &start,
+		Comments: []Comment{
+			{Body: "Handle the error", Author: "alice", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), DiffHunk: "@@ -10,3 +10,3 @@"},
+			{Body: "Which one?", Author: "bob", CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	in := NewFixInput(thread, "package main\n")
+	if in.Path != "main.go" || *in.Line != 12 || *in.StartLine != 10 || in.DiffHunk != "@@ -10,3 +10,3 @@" || in.FileContent != "package main\n" {
+		t.Errorf("unexpected input: %+v", in)
+	}
+	if len(in.Comments) != 2 || in.Comments[1].Author != "bob" || in.Comments[0].CreatedAt != "2026-01-01T00:00:00Z" {
+		t.Errorf("unexpected comments: %+v", in.Comments)
+	}
+}
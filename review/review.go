@@ -2,6 +2,7 @@ package review
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -20,18 +21,26 @@ type Comment struct {
 
 // Thread represents an inline review thread.
 type Thread struct {
-	ID         string    `json:"id"`
-	IsResolved bool      `json:"is_resolved"`
-	IsOutdated bool      `json:"is_outdated"`
-	Path       string    `json:"path"`
-	Line       *int      `json:"line,omitempty"`
-	Comments   []Comment `json:"comments"`
+	ID                string    `json:"id"`
+	IsResolved        bool      `json:"is_resolved"`
+	IsOutdated        bool      `json:"is_outdated"`
+	Path              string    `json:"path"`
+	Line              *int      `json:"line,omitempty"`
+	StartLine         *int      `json:"start_line,omitempty"`
+	OriginalLine      *int      `json:"original_line,omitempty"`
+	OriginalStartLine *int      `json:"original_start_line,omitempty"`
+	DiffSide          string    `json:"diff_side,omitempty"`    // LEFT or RIGHT
+	SubjectType       string    `json:"subject_type,omitempty"` // LINE or FILE
+	Comments          []Comment `json:"comments"`
 }
 
 // Data holds all review data for a PR.
 type Data struct {
-	Threads    []Thread  `json:"threads"`
-	PRComments []Comment `json:"pr_comments"`
+	Title        string            `json:"title,omitempty"`
+	HeadCommitID string            `json:"head_commit_id,omitempty"`
+	Threads      []Thread          `json:"threads"`
+	PRComments   []Comment         `json:"pr_comments"`
+	Files        map[string]string `json:"files,omitempty"` // head file contents keyed by path
 }
 
 // ClassifyInputThread is a thread entry sent to the classifier.
@@ -40,6 +49,8 @@ type ClassifyInputThread struct {
 	Type               string                 `json:"type"`
 	Path               string                 `json:"path,omitempty"`
 	Line               *int                   `json:"line,omitempty"`
+	StartLine          *int                   `json:"start_line,omitempty"`
+	SubjectType        string                 `json:"subject_type,omitempty"`
 	IsResolvedOnGitHub bool                   `json:"is_resolved_on_github"`
 	Comments           []ClassifyInputComment `json:"comments"`
 }
@@ -67,18 +78,22 @@ type ClassifyInput struct {
 
 // ClassifyOutputThread is a classified thread result.
 type ClassifyOutputThread struct {
-	ThreadID   string `json:"thread_id"`
-	Category   string `json:"category"`
-	IsResolved bool   `json:"is_resolved"`
-	Reason     string `json:"reason"`
+	ThreadID   string          `json:"thread_id"`
+	Category   string          `json:"category"`
+	IsResolved bool            `json:"is_resolved"`
+	Reason     string          `json:"reason"`
+	Confidence float64         `json:"confidence"`
+	Raw        json.RawMessage `json:"-"` // raw model response fragment
 }
 
 // ClassifyOutputPRComment is a classified PR comment result.
 type ClassifyOutputPRComment struct {
-	ID         string `json:"id"`
-	Category   string `json:"category"`
-	IsResolved bool   `json:"is_resolved"`
-	Reason     string `json:"reason"`
+	ID         string          `json:"id"`
+	Category   string          `json:"category"`
+	IsResolved bool            `json:"is_resolved"`
+	Reason     string          `json:"reason"`
+	Confidence float64         `json:"confidence"`
+	Raw        json.RawMessage `json:"-"` // raw model response fragment
 }
 
 // ClassifyOutput is the full output from the classifier.
@@ -95,46 +110,181 @@ type CommentClassifier interface {
 
 // UnresolvedComment is the JSON output structure for CLI results.
 type UnresolvedComment struct {
-	ThreadID  string `json:"thread_id,omitempty"`
-	CommentID int64  `json:"comment_id"`
-	Type      string `json:"type"`
-	Path      string `json:"path,omitempty"`
-	Line      *int   `json:"line,omitempty"`
-	CommitID  string `json:"commit_id,omitempty"`
-	DiffHunk  string `json:"diff_hunk,omitempty"`
-	Author    string `json:"author"`
-	Body      string `json:"body"`
-	URL       string `json:"url"`
-	Category  string `json:"category"`
-	Resolved  bool   `json:"resolved"`
-	Reason    string `json:"reason"`
+	ThreadID          string       `json:"thread_id,omitempty"`
+	CommentID         int64        `json:"comment_id"`
+	Type              string       `json:"type"`
+	Path              string       `json:"path,omitempty"`
+	Line              *int         `json:"line,omitempty"`
+	StartLine         *int         `json:"start_line,omitempty"`
+	OriginalLine      *int         `json:"original_line,omitempty"`
+	OriginalStartLine *int         `json:"original_start_line,omitempty"`
+	DiffSide          string       `json:"diff_side,omitempty"`
+	SubjectType       string       `json:"subject_type,omitempty"`
+	CommitID          string       `json:"commit_id,omitempty"`
+	DiffHunk          string       `json:"diff_hunk,omitempty"`
+	Author            string       `json:"author"`
+	Body              string       `json:"body"`
+	URL               string       `json:"url"`
+	CreatedAt         *time.Time   `json:"created_at,omitempty"`
+	LastActivityAt    *time.Time   `json:"last_activity_at,omitempty"` // time of the last comment of the thread; edits are not counted
+	Category          string       `json:"category"`
+	Resolved          bool         `json:"resolved"`
+	ResolvedBy        string       `json:"resolved_by,omitempty"` // github, suggestion, or classifier
+	Confidence        float64      `json:"confidence,omitempty"`
+	Reason            string       `json:"reason"`
+	Suggestion        *string      `json:"suggestion,omitempty"`
+	SuggestionApplied bool         `json:"suggestion_applied,omitempty"`
+	Replies           []Reply      `json:"replies,omitempty"`
+	Explanation       *Explanation `json:"explanation,omitempty"`
+	DraftReply        string       `json:"draft_reply,omitempty"`
+}
+
+// Explanation holds the classifier input and the raw model response for a single item.
+type Explanation struct {
+	Input    json.RawMessage `json:"input"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Reply is a follow-up comment in a review thread.
+type Reply struct {
+	Author     string    `json:"author"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	URL        string    `json:"url"`
+	DatabaseID int64     `json:"database_id"`
+}
+
+// Option configures Analyze.
+type Option func(*options)
+
+type options struct {
+	showAll   bool
+	explain   bool
+	drafter   ReplyDrafter
+	batchSize int
+	handler   func([]UnresolvedComment) error
+}
+
+// WithBatchSize classifies the threads and PR comments in batches of n items
+// instead of all at once.
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = n
+	}
+}
+
+// WithHandler passes the results of each batch to h as soon as the batch is
+// classified, instead of returning them from Analyze.
+func WithHandler(h func([]UnresolvedComment) error) Option {
+	return func(o *options) {
+		o.handler = h
+	}
+}
+
+// WithExplain attaches the classifier input and the raw model response to each result.
+func WithExplain() Option {
+	return func(o *options) {
+		o.explain = true
+	}
 }
 
 // Analyze classifies and filters review comments, returning unresolved ones (or all if showAll is true).
-func Analyze(ctx context.Context, data *Data, classifier CommentClassifier, showAll bool) ([]UnresolvedComment, error) {
+func Analyze(ctx context.Context, data *Data, classifier CommentClassifier, showAll bool, opts ...Option) ([]UnresolvedComment, error) {
+	o := &options{showAll: showAll}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	results := []UnresolvedComment{}
+	for _, batch := range splitData(data, o.batchSize) {
+		r, err := analyzeBatch(ctx, batch, classifier, o)
+		if err != nil {
+			return nil, err
+		}
+		if o.handler != nil {
+			if err := o.handler(r); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		results = append(results, r...)
+	}
+	return results, nil
+}
+
+// splitData splits the threads and PR comments of data into batches of up to
+// size items. With size <= 0, data is returned as a single batch.
+func splitData(data *Data, size int) []*Data {
 	if len(data.Threads) == 0 && len(data.PRComments) == 0 {
-		return []UnresolvedComment{}, nil
+		return nil
+	}
+	total := len(data.Threads) + len(data.PRComments)
+	if size <= 0 || total <= size {
+		return []*Data{data}
+	}
+	var batches []*Data
+	for start := 0; start < total; start += size {
+		end := min(start+size, total)
+		b := &Data{Title: data.Title, HeadCommitID: data.HeadCommitID, Files: data.Files}
+		if start < len(data.Threads) {
+			b.Threads = data.Threads[start:min(end, len(data.Threads))]
+		}
+		if end > len(data.Threads) {
+			b.PRComments = data.PRComments[max(start-len(data.Threads), 0) : end-len(data.Threads)]
+		}
+		batches = append(batches, b)
 	}
+	return batches
+}
 
-	input := buildClassifyInput(data)
+// analyzeBatch classifies and filters the review comments of a single batch.
+func analyzeBatch(ctx context.Context, data *Data, classifier CommentClassifier, o *options) ([]UnresolvedComment, error) {
+	// Suggested changes already present in the head are resolved without asking the classifier.
+	a := &analysis{suggestions: detectSuggestions(data)}
+	a.input = buildClassifyInput(data, a.suggestions)
+
+	a.output = &ClassifyOutput{}
+	if len(a.input.Threads) > 0 || len(a.input.PRComments) > 0 {
+		var err error
+		a.output, err = classifier.ClassifyAll(ctx, a.input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to classify comments: %w", err)
+		}
+	}
 
-	output, err := classifier.ClassifyAll(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to classify comments: %w", err)
+	if o.drafter != nil {
+		var err error
+		a.drafts, err = draftReplies(ctx, data, a.output, o.drafter)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return buildResults(data, output, showAll), nil
+	return buildResults(data, a, o), nil
 }
 
-func buildClassifyInput(data *Data) *ClassifyInput {
+// analysis holds the intermediate state of Analyze.
+type analysis struct {
+	input       *ClassifyInput
+	output      *ClassifyOutput
+	suggestions map[string]suggestionState
+	drafts      map[string]string // keyed by thread ID or PR comment ID
+}
+
+func buildClassifyInput(data *Data, suggestions map[string]suggestionState) *ClassifyInput {
 	input := &ClassifyInput{}
 
 	for _, t := range data.Threads {
+		if suggestions[t.ID].applied {
+			continue
+		}
 		ct := ClassifyInputThread{
 			ThreadID:           t.ID,
 			Type:               "inline",
 			Path:               t.Path,
 			Line:               t.Line,
+			StartLine:          t.StartLine,
+			SubjectType:        t.SubjectType,
 			IsResolvedOnGitHub: t.IsResolved,
 		}
 		for _, c := range t.Comments {
@@ -159,8 +309,18 @@ func buildClassifyInput(data *Data) *ClassifyInput {
 	return input
 }
 
-func buildResults(data *Data, output *ClassifyOutput, showAll bool) []UnresolvedComment {
+func buildResults(data *Data, a *analysis, o *options) []UnresolvedComment {
 	var results []UnresolvedComment
+	input, output, suggestions := a.input, a.output, a.suggestions
+
+	threadInputs := make(map[string]ClassifyInputThread, len(input.Threads))
+	for _, t := range input.Threads {
+		threadInputs[t.ThreadID] = t
+	}
+	commentInputs := make(map[string]ClassifyInputPRComment, len(input.PRComments))
+	for _, c := range input.PRComments {
+		commentInputs[c.ID] = c
+	}
 
 	threadMap := make(map[string]*ClassifyOutputThread, len(output.Threads))
 	for i := range output.Threads {
@@ -170,23 +330,39 @@ func buildResults(data *Data, output *ClassifyOutput, showAll bool) []Unresolved
 	for _, t := range data.Threads {
 		classified, ok := threadMap[t.ID]
 		resolved := t.IsResolved
+		resolvedBy := ""
+		if resolved {
+			resolvedBy = "github"
+		}
 		category := "unknown"
 		reason := ""
-		if ok {
+		var confidence float64
+		suggestion, hasSuggestion := suggestions[t.ID]
+		switch {
+		case suggestion.applied:
+			category = "suggestion"
+			resolved = true
+			resolvedBy = "suggestion"
+			reason = "The suggested change is present in the head commit"
+			confidence = 1
+		case ok:
 			category = classified.Category
 			reason = classified.Reason
-			if !resolved {
-				resolved = classified.IsResolved
+			confidence = classified.Confidence
+			if !resolved && classified.IsResolved {
+				resolved = true
+				resolvedBy = "classifier"
 			}
 		}
 
-		if !showAll && resolved {
+		if !o.showAll && resolved {
 			continue
 		}
 
 		// Use the first comment as the representative.
 		var author, body, url, commitID, diffHunk string
 		var commentID int64
+		var createdAt, lastActivityAt *time.Time
 		if len(t.Comments) > 0 {
 			author = t.Comments[0].Author
 			body = t.Comments[0].Body
@@ -194,23 +370,56 @@ func buildResults(data *Data, output *ClassifyOutput, showAll bool) []Unresolved
 			commentID = t.Comments[0].DatabaseID
 			commitID = t.Comments[0].CommitID
 			diffHunk = t.Comments[0].DiffHunk
+			createdAt = timeOrNil(t.Comments[0].CreatedAt)
+			lastActivityAt = timeOrNil(t.Comments[len(t.Comments)-1].CreatedAt)
 		}
 
-		results = append(results, UnresolvedComment{
-			ThreadID:  t.ID,
-			CommentID: commentID,
-			Type:      "thread",
-			Path:      t.Path,
-			Line:      t.Line,
-			CommitID:  commitID,
-			DiffHunk:  diffHunk,
-			Author:    author,
-			Body:      body,
-			URL:       url,
-			Category:  category,
-			Resolved:  resolved,
-			Reason:    reason,
-		})
+		r := UnresolvedComment{
+			ThreadID:          t.ID,
+			CommentID:         commentID,
+			Type:              "thread",
+			Path:              t.Path,
+			Line:              t.Line,
+			StartLine:         t.StartLine,
+			OriginalLine:      t.OriginalLine,
+			OriginalStartLine: t.OriginalStartLine,
+			DiffSide:          t.DiffSide,
+			SubjectType:       t.SubjectType,
+			CommitID:          commitID,
+			DiffHunk:          diffHunk,
+			Author:            author,
+			Body:              body,
+			URL:               url,
+			CreatedAt:         createdAt,
+			LastActivityAt:    lastActivityAt,
+			Category:          category,
+			Resolved:          resolved,
+			ResolvedBy:        resolvedBy,
+			Confidence:        confidence,
+			Reason:            reason,
+			SuggestionApplied: suggestion.applied,
+		}
+		if hasSuggestion {
+			r.Suggestion = &suggestion.text
+		}
+		for _, c := range t.Comments[min(1, len(t.Comments)):] {
+			r.Replies = append(r.Replies, Reply{
+				Author:     c.Author,
+				Body:       c.Body,
+				CreatedAt:  c.CreatedAt,
+				URL:        c.URL,
+				DatabaseID: c.DatabaseID,
+			})
+		}
+		if in, found := threadInputs[t.ID]; found && o.explain {
+			var response json.RawMessage
+			if ok {
+				response = classified.Raw
+			}
+			r.Explanation = explain(in, response)
+		}
+		r.DraftReply = a.drafts[t.ID]
+		results = append(results, r)
 	}
 
 	commentMap := make(map[string]*ClassifyOutputPRComment, len(output.PRComments))
@@ -221,29 +430,100 @@ func buildResults(data *Data, output *ClassifyOutput, showAll bool) []Unresolved
 	for _, c := range data.PRComments {
 		classified, ok := commentMap[c.ID]
 		resolved := false
+		resolvedBy := ""
 		category := "unknown"
 		reason := ""
+		var confidence float64
 		if ok {
 			category = classified.Category
 			resolved = classified.IsResolved
 			reason = classified.Reason
+			confidence = classified.Confidence
+			if resolved {
+				resolvedBy = "classifier"
+			}
 		}
 
-		if !showAll && resolved {
+		if !o.showAll && resolved {
 			continue
 		}
 
-		results = append(results, UnresolvedComment{
-			CommentID: c.DatabaseID,
-			Type:      "comment",
-			Author:    c.Author,
-			Body:      c.Body,
-			URL:       c.URL,
-			Category:  category,
-			Resolved:  resolved,
-			Reason:    reason,
-		})
+		r := UnresolvedComment{
+			CommentID:      c.DatabaseID,
+			Type:           "comment",
+			Author:         c.Author,
+			Body:           c.Body,
+			URL:            c.URL,
+			CreatedAt:      timeOrNil(c.CreatedAt),
+			LastActivityAt: timeOrNil(c.CreatedAt),
+			Category:       category,
+			Resolved:       resolved,
+			ResolvedBy:     resolvedBy,
+			Confidence:     confidence,
+			Reason:         reason,
+		}
+		if in, found := commentInputs[c.ID]; found && o.explain {
+			var response json.RawMessage
+			if ok {
+				response = classified.Raw
+			}
+			r.Explanation = explain(in, response)
+		}
+		r.DraftReply = a.drafts[c.ID]
+		results = append(results, r)
 	}
 
 	return results
 }
+
+// explain builds an Explanation from a classifier input entry and the raw
+// model response fragment for it.
+func explain(in any, response json.RawMessage) *Explanation {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil
+	}
+	return &Explanation{Input: b, Response: response}
+}
+
+// Unresolved returns the results that are not resolved.
+func Unresolved(results []UnresolvedComment) []UnresolvedComment {
+	unresolved := []UnresolvedComment{}
+	for _, r := range results {
+		if !r.Resolved {
+			unresolved = append(unresolved, r)
+		}
+	}
+	return unresolved
+}
+
+// AutoResolvable returns the threads that are still open on GitHub but were
+// found resolved by the classifier with at least minConfidence, or by an
+// applied suggestion.
+func AutoResolvable(results []UnresolvedComment, minConfidence float64) []UnresolvedComment {
+	var threads []UnresolvedComment
+	for _, r := range results {
+		if r.Type != "thread" || !r.Resolved {
+			continue
+		}
+		switch r.ResolvedBy {
+		case "suggestion":
+		case "classifier":
+			if r.Confidence < minConfidence {
+				continue
+			}
+		default:
+			continue
+		}
+		threads = append(threads, r)
+	}
+	return threads
+}
+
+// timeOrNil returns a pointer to t, or nil if t is unknown.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -2,6 +2,7 @@ package review
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -133,7 +134,7 @@ func TestBuildClassifyInput(t *testing.T) {
 		},
 	}
 
-	input := buildClassifyInput(data)
+	input := buildClassifyInput(data, nil)
 
 	if len(input.Threads) != 1 {
 		t.Fatalf("expected 1 thread, got %d", len(input.Threads))
@@ -155,3 +156,261 @@ func TestBuildClassifyInput(t *testing.T) {
 		t.Errorf("expected PR comment ID PC1, got %s", input.PRComments[0].ID)
 	}
 }
+
+func TestAnalyzeCarriesLocation(t *testing.T) {
+	start := 40
+	line := 48
+	original := 12
+	data := &Data{
+		Threads: []Thread{
+			{ID: "T1", Path: "main.go", StartLine: &start, Line: &line, DiffSide: "RIGHT", SubjectType: "LINE", Comments: []Comment{{ID: "C1", Body: "Range"}}},
+			{ID: "T2", Path: "main.go", IsOutdated: true, OriginalLine: &original, OriginalStartLine: &original, SubjectType: "LINE", Comments: []Comment{{ID: "C2", Body: "Outdated"}}},
+			{ID: "T3", Path: "go.mod", SubjectType: "FILE", Comments: []Comment{{ID: "C3", Body: "File"}}},
+		},
+	}
+	mock := &mockClassifier{output: &ClassifyOutput{}}
+
+	results, err := Analyze(context.Background(), data, mock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if r := results[0]; r.StartLine == nil || *r.StartLine != 40 || r.Line == nil || *r.Line != 48 || r.DiffSide != "RIGHT" {
+		t.Errorf("unexpected range result: %+v", r)
+	}
+	if r := results[1]; r.Line != nil || r.OriginalLine == nil || *r.OriginalLine != 12 || r.OriginalStartLine == nil {
+		t.Errorf("unexpected outdated result: %+v", r)
+	}
+	if r := results[2]; r.SubjectType != "FILE" {
+		t.Errorf("unexpected file-level result: %+v", r)
+	}
+}
+
+func TestAnalyzeReplies(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &Data{
+		Threads: []Thread{
+			{
+				ID:   "T1",
+				Path: "main.go",
+				Comments: []Comment{
+					{ID: "C1", DatabaseID: 1, Body: "Why?", Author: "alice", CreatedAt: now},
+					{ID: "C2", DatabaseID: 2, Body: "Because", Author: "bob", CreatedAt: now.Add(time.Hour), URL: "https://example.com/2"},
+				},
+			},
+		},
+	}
+	mock := &mockClassifier{output: &ClassifyOutput{}}
+
+	results, err := Analyze(context.Background(), data, mock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Body != "Why?" {
+		t.Errorf("expected the first comment as the representative, got %q", results[0].Body)
+	}
+	if r := results[0]; r.CreatedAt == nil || !r.CreatedAt.Equal(now) || r.LastActivityAt == nil || !r.LastActivityAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected times: created_at %v, last_activity_at %v", r.CreatedAt, r.LastActivityAt)
+	}
+	replies := results[0].Replies
+	if len(replies) != 1 {
+		t.Fatalf("expected 1 reply, got %d", len(replies))
+	}
+	if replies[0].Author != "bob" || replies[0].DatabaseID != 2 || replies[0].URL != "https://example.com/2" || !replies[0].CreatedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected reply: %+v", replies[0])
+	}
+}
+
+func TestAnalyzeWithExplain(t *testing.T) {
+	data := &Data{
+		Threads: []Thread{
+			{ID: "T1", Path: "main.go", Comments: []Comment{{ID: "C1", Body: "Fix this", Author: "alice"}}},
+		},
+		PRComments: []Comment{
+			{ID: "PC1", Body: "Overall", Author: "bob"},
+		},
+	}
+	mock := &mockClassifier{
+		output: &ClassifyOutput{
+			Threads: []ClassifyOutputThread{
+				{ThreadID: "T1", Category: "suggestion", Reason: "Not addressed", Raw: []byte(`{"thread_id":"T1"}`)},
+			},
+		},
+	}
+
+	results, err := Analyze(context.Background(), data, mock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Explanation != nil {
+			t.Error("explanation should be nil without WithExplain")
+		}
+	}
+
+	results, err = Analyze(context.Background(), data, mock, false, WithExplain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	e := results[0].Explanation
+	if e == nil {
+		t.Fatal("expected explanation for thread")
+	}
+	if !strings.Contains(string(e.Input), `"thread_id":"T1"`) || string(e.Response) != `{"thread_id":"T1"}` {
+		t.Errorf("unexpected thread explanation: input=%s response=%s", e.Input, e.Response)
+	}
+	e = results[1].Explanation
+	if e == nil || !strings.Contains(string(e.Input), `"id":"PC1"`) || e.Response != nil {
+		t.Errorf("unexpected PR comment explanation: %+v", e)
+	}
+}
+
+func TestAutoResolvable(t *testing.T) {
+	line := 3
+	data := &Data{
+		Threads: []Thread{
+			{ID: "T1", Path: "a.go", Comments: []Comment{{ID: "C1", Body: "Fix this"}, {ID: "C2", Body: "Done"}}},
+			{ID: "T2", Path: "a.go", Comments: []Comment{{ID: "C3", Body: "Fix that"}, {ID: "C4", Body: "Maybe"}}},
+			{ID: "T3", Path: "a.go", IsResolved: true, Comments: []Comment{{ID: "C5", Body: "Already resolved"}}},
+			{ID: "T4", Path: "a.go", Comments: []Comment{{ID: "C6", Body: "Still open"}}},
+			{ID: "T5", Path: "b.go", Line: &line, Comments: []Comment{{ID: "C7", Body: "```suggestion\nfoo()\n```"}}},
+		},
+		PRComments: []Comment{
+			{ID: "PC1", Body: "Add tests"},
+		},
+		Files: map[string]string{"b.go": "package b\n\nfoo()\n"},
+	}
+	mock := &mockClassifier{
+		output: &ClassifyOutput{
+			Threads: []ClassifyOutputThread{
+				{ThreadID: "T1", Category: "suggestion", IsResolved: true, Confidence: 0.95},
+				{ThreadID: "T2", Category: "suggestion", IsResolved: true, Confidence: 0.5},
+				{ThreadID: "T3", Category: "suggestion", IsResolved: true, Confidence: 1},
+				{ThreadID: "T4", Category: "issue", IsResolved: false, Confidence: 0.9},
+			},
+			PRComments: []ClassifyOutputPRComment{
+				{ID: "PC1", Category: "suggestion", IsResolved: true, Confidence: 1},
+			},
+		},
+	}
+
+	results, err := Analyze(context.Background(), data, mock, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResolvedBy := map[string]string{"T1": "classifier", "T2": "classifier", "T3": "github", "T4": "", "T5": "suggestion"}
+	for _, r := range results {
+		if r.Type == "thread" && r.ResolvedBy != wantResolvedBy[r.ThreadID] {
+			t.Errorf("%s: got resolved_by %q, want %q", r.ThreadID, r.ResolvedBy, wantResolvedBy[r.ThreadID])
+		}
+	}
+
+	got := AutoResolvable(results, 0.9)
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.ThreadID)
+	}
+	if strings.Join(ids, ",") != "T1,T5" {
+		t.Errorf("got auto-resolvable threads %v, want [T1 T5]", ids)
+	}
+
+	unresolved := Unresolved(results)
+	if len(unresolved) != 1 || unresolved[0].ThreadID != "T4" {
+		t.Errorf("unexpected unresolved results: %+v", unresolved)
+	}
+}
+
+type countingClassifier struct {
+	calls []*ClassifyInput
+}
+
+func (c *countingClassifier) ClassifyAll(_ context.Context, input *ClassifyInput) (*ClassifyOutput, error) {
+	c.calls = append(c.calls, input)
+	out := &ClassifyOutput{}
+	for _, t := range input.Threads {
+		out.Threads = append(out.Threads, ClassifyOutputThread{ThreadID: t.ThreadID, Category: "issue"})
+	}
+	for _, pc := range input.PRComments {
+		out.PRComments = append(out.PRComments, ClassifyOutputPRComment{ID: pc.ID, Category: "question"})
+	}
+	return out, nil
+}
+
+func (c *countingClassifier) Close() {}
+
+func TestAnalyzeBatches(t *testing.T) {
+	data := &Data{
+		Threads: []Thread{
+			{ID: "T1", Comments: []Comment{{Body: "a"}}},
+			{ID: "T2", Comments: []Comment{{Body: "b"}}},
+			{ID: "T3", Comments: []Comment{{Body: "c"}}},
+		},
+		PRComments: []Comment{
+			{ID: "PC1", Body: "d"},
+			{ID: "PC2", Body: "e"},
+		},
+	}
+	classifier := &countingClassifier{}
+	var batches [][]UnresolvedComment
+	results, err := Analyze(context.Background(), data, classifier, false, WithBatchSize(2), WithHandler(func(r []UnresolvedComment) error {
+		batches = append(batches, r)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("results should be passed to the handler, got %d", len(results))
+	}
+	if len(classifier.calls) != 3 || len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d calls and %d batches", len(classifier.calls), len(batches))
+	}
+	second := classifier.calls[1]
+	if len(second.Threads) != 1 || second.Threads[0].ThreadID != "T3" || len(second.PRComments) != 1 || second.PRComments[0].ID != "PC1" {
+		t.Errorf("unexpected second batch: %+v", second)
+	}
+	var ids []string
+	for _, b := range batches {
+		for _, r := range b {
+			ids = append(ids, r.ThreadID+r.Body)
+		}
+	}
+	if got := strings.Join(ids, ","); got != "T1a,T2b,T3c,d,e" {
+		t.Errorf("unexpected results: %s", got)
+	}
+
+	classifier = &countingClassifier{}
+	results, err = Analyze(context.Background(), data, classifier, false, WithBatchSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classifier.calls) != 1 || len(results) != 5 {
+		t.Errorf("expected a single batch with 5 results, got %d calls and %d results", len(classifier.calls), len(results))
+	}
+}
+
+func TestAnalyzeUnknownTimes(t *testing.T) {
+	data := &Data{
+		PRComments: []Comment{{ID: "PC1", Body: "Overall", Author: "bob"}},
+	}
+	mock := &mockClassifier{output: &ClassifyOutput{}}
+
+	results, err := Analyze(context.Background(), data, mock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].CreatedAt != nil || results[0].LastActivityAt != nil {
+		t.Errorf("unknown times should be omitted, got %v and %v", results[0].CreatedAt, results[0].LastActivityAt)
+	}
+}
@@ -0,0 +1,313 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var suggestionFenceRegexp = regexp.MustCompile("^(\\s*)(`{3,}|~{3,})\\s*suggestion\\s*$")
+
+// SuggestionBlock is a ```suggestion block found by FindSuggestionBlocks.
+type SuggestionBlock struct {
+	// Open and Close are the indexes of the opening and closing fence lines.
+	Open  int
+	Close int
+	// Indent is the indentation of the opening fence, and Fence its backticks or tildes.
+	Indent string
+	Fence  string
+}
+
+// FindSuggestionBlocks returns the terminated ```suggestion blocks in lines.
+// A block is closed by a line of at least as many of the same fence characters.
+func FindSuggestionBlocks(lines []string) []SuggestionBlock {
+	var blocks []SuggestionBlock
+	for i := 0; i < len(lines); i++ {
+		m := suggestionFenceRegexp.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		b := SuggestionBlock{Open: i, Close: -1, Indent: m[1], Fence: m[2]}
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(trimmed, b.Fence) && strings.Trim(trimmed, b.Fence[:1]) == "" {
+				b.Close = j
+				break
+			}
+		}
+		if b.Close < 0 {
+			break
+		}
+		blocks = append(blocks, b)
+		i = b.Close
+	}
+	return blocks
+}
+
+// ParseSuggestions extracts the replacement text of each ```suggestion block in body.
+func ParseSuggestions(body string) []string {
+	lines := splitLines(body)
+	var suggestions []string
+	for _, b := range FindSuggestionBlocks(lines) {
+		suggestions = append(suggestions, strings.Join(lines[b.Open+1:b.Close], "\n"))
+	}
+	return suggestions
+}
+
+// ThreadSuggestion returns the first suggested change found in the thread's comments.
+func ThreadSuggestion(t Thread) (string, bool) {
+	for _, c := range t.Comments {
+		if s := ParseSuggestions(c.Body); len(s) > 0 {
+			return s[0], true
+		}
+	}
+	return "", false
+}
+
+// SuggestionPaths returns the file paths of unresolved threads that contain suggested changes.
+func SuggestionPaths(data *Data) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, t := range data.Threads {
+		if t.IsResolved || t.Path == "" || seen[t.Path] {
+			continue
+		}
+		if _, ok := ThreadSuggestion(t); !ok {
+			continue
+		}
+		seen[t.Path] = true
+		paths = append(paths, t.Path)
+	}
+	return paths
+}
+
+// Suggestion is a suggested change from a review thread.
+type Suggestion struct {
+	ThreadID  string
+	Path      string
+	StartLine int
+	EndLine   int
+	Lines     []string // replacement lines
+	Original  []string // commented lines when the suggestion was made, if known
+	Author    string
+	URL       string
+}
+
+// PendingSuggestions returns the suggested changes of threads that are not
+// resolved on GitHub and are still attached to a line in the head.
+func PendingSuggestions(data *Data) []Suggestion {
+	var suggestions []Suggestion
+	for _, t := range data.Threads {
+		if t.IsResolved || t.Path == "" {
+			continue
+		}
+		text, ok := ThreadSuggestion(t)
+		if !ok {
+			continue
+		}
+		start, end, ok := lineRange(t)
+		if !ok {
+			continue
+		}
+		s := Suggestion{
+			ThreadID:  t.ID,
+			Path:      t.Path,
+			StartLine: start,
+			EndLine:   end,
+			Original:  originalLines(t, end-start+1),
+		}
+		if text != "" {
+			s.Lines = splitLines(text)
+		}
+		if len(t.Comments) > 0 {
+			s.Author = t.Comments[0].Author
+			s.URL = t.Comments[0].URL
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions
+}
+
+// IsAppliedTo reports whether content already has the suggested lines at, or
+// near, the suggestion's position.
+func (s Suggestion) IsAppliedTo(content string) bool {
+	return suggestionApplied(content, s.StartLine, strings.Join(s.Lines, "\n"), s.Original)
+}
+
+// MatchesOriginal reports whether content still has the originally commented
+// lines at the suggestion's position. It returns true when they are unknown.
+func (s Suggestion) MatchesOriginal(content string) bool {
+	if s.Original == nil {
+		return true
+	}
+	lines := splitLines(content)
+	if s.StartLine < 1 || s.EndLine > len(lines) {
+		return false
+	}
+	return equalLines(lines[s.StartLine-1:s.EndLine], s.Original)
+}
+
+type suggestionState struct {
+	text    string
+	applied bool
+}
+
+// detectSuggestions finds suggested changes in threads and checks whether they
+// are already present in the head file content fetched into data.Files. The
+// local checkout is not consulted.
+func detectSuggestions(data *Data) map[string]suggestionState {
+	states := map[string]suggestionState{}
+	for _, t := range data.Threads {
+		text, ok := ThreadSuggestion(t)
+		if !ok {
+			continue
+		}
+		state := suggestionState{text: text}
+		content, hasContent := data.Files[t.Path]
+		if start, end, ok := lineRange(t); ok && !t.IsResolved && hasContent {
+			state.applied = suggestionApplied(content, start, text, originalLines(t, end-start+1))
+		}
+		states[t.ID] = state
+	}
+	return states
+}
+
+// suggestionWindow is how many lines a suggestion may have moved from the
+// commented position, e.g. by changes above it, and still be found applied.
+const suggestionWindow = 5
+
+// suggestionApplied reports whether the lines of content starting at startLine,
+// or within suggestionWindow lines of it, match suggestion. A suggestion
+// identical to the original code is never considered applied. A match at a
+// shifted line only counts when the original lines are known and no longer
+// found within the window, since a short suggestion may match a nearby line
+// of unchanged code.
+func suggestionApplied(content string, startLine int, suggestion string, original []string) bool {
+	if suggestion == "" || startLine < 1 {
+		// A deletion leaves nothing to compare against.
+		return false
+	}
+	want := splitLines(suggestion)
+	if original != nil && equalLines(original, want) {
+		return false
+	}
+	lines := splitLines(content)
+	if original != nil && linesAt(lines, startLine, original) {
+		return false
+	}
+	if linesAt(lines, startLine, want) {
+		return true
+	}
+	if original == nil || withinWindow(lines, startLine, original) {
+		return false
+	}
+	return withinWindow(lines, startLine, want)
+}
+
+// withinWindow reports whether lines has want starting within suggestionWindow
+// lines of start.
+func withinWindow(lines []string, start int, want []string) bool {
+	for d := range suggestionWindow + 1 {
+		if linesAt(lines, start+d, want) || (d > 0 && linesAt(lines, start-d, want)) {
+			return true
+		}
+	}
+	return false
+}
+
+// linesAt reports whether lines has want starting at line start (1-based).
+func linesAt(lines []string, start int, want []string) bool {
+	end := start - 1 + len(want)
+	if start < 1 || end > len(lines) {
+		return false
+	}
+	return equalLines(lines[start-1:end], want)
+}
+
+// originalLines returns the n commented lines as they were when the thread was
+// started, taken from the tail of the first comment's diff hunk.
+func originalLines(t Thread, n int) []string {
+	if len(t.Comments) == 0 {
+		return nil
+	}
+	return HunkTail(t.Comments[0].DiffHunk, n)
+}
+
+// lineRange returns the first and last line the thread is attached to in the head.
+func lineRange(t Thread) (int, int, bool) {
+	if t.Line == nil || t.DiffSide == "LEFT" {
+		return 0, 0, false
+	}
+	start := *t.Line
+	if t.StartLine != nil && *t.StartLine <= start {
+		start = *t.StartLine
+	}
+	return start, *t.Line, true
+}
+
+// HunkTail returns the last n lines of the new side of a diff hunk, without the diff prefix.
+func HunkTail(diffHunk string, n int) []string {
+	if n < 1 {
+		return nil
+	}
+	var lines []string
+	for _, l := range hunkLines(diffHunk) {
+		if strings.HasPrefix(l, "-") {
+			continue
+		}
+		if l != "" {
+			l = l[1:]
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) < n {
+		return nil
+	}
+	return lines[len(lines)-n:]
+}
+
+// DiffTail returns at most the last n lines of a diff hunk, keeping the diff
+// prefix of each line so that they can be colored.
+func DiffTail(diffHunk string, n int) []string {
+	if n < 1 {
+		return nil
+	}
+	lines := hunkLines(diffHunk)
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// hunkLines returns the lines of a diff hunk, without the hunk header and
+// "\ No newline at end of file" markers.
+func hunkLines(diffHunk string) []string {
+	if diffHunk == "" {
+		return nil
+	}
+	var lines []string
+	for _, l := range splitLines(diffHunk) {
+		if strings.HasPrefix(l, "@@") || strings.HasPrefix(l, `\`) {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimRight(a[i], " \t") != strings.TrimRight(b[i], " \t") {
+			return false
+		}
+	}
+	return true
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
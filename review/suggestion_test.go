@@ -0,0 +1,307 @@
+package review
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseSuggestions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "single line",
+			body: "Use a constant here.\n```suggestion\nconst maxRetry = 3\n```",
+			want: []string{"const maxRetry = 3"},
+		},
+		{
+			name: "multi line with CRLF",
+			body: "```suggestion\r\nif err != nil {\r\n\treturn err\r\n}\r\n```\r\n",
+			want: []string{"if err != nil {\n\treturn err\n}"},
+		},
+		{
+			name: "deletion",
+			body: "```suggestion\n```",
+			want: []string{""},
+		},
+		{
+			name: "longer fence with nested fence",
+			body: "````suggestion\n```go\nfoo()\n```\n````",
+			want: []string{"```go\nfoo()\n```"},
+		},
+		{
+			name: "longer closing fence",
+			body: "```suggestion\nfoo()\n````",
+			want: []string{"foo()"},
+		},
+		{
+			name: "multiple blocks",
+			body: "```suggestion\na\n```\nor\n```suggestion\nb\n```",
+			want: []string{"a", "b"},
+		},
+		{
+			name: "other code block",
+			body: "```go\nfoo()\n```",
+			want: nil,
+		},
+		{
+			name: "unterminated",
+			body: "```suggestion\nfoo()",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSuggestions(tt.body)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("suggestion %d: got %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestionApplied(t *testing.T) {
+	content := "package main\n\nconst maxRetry = 3\n\nfunc main() {}\n"
+	tests := []struct {
+		name       string
+		startLine  int
+		suggestion string
+		original   []string
+		want       bool
+	}{
+		{"match", 3, "const maxRetry = 3", []string{"const maxRetry = 5"}, true},
+		{"trailing whitespace", 3, "const maxRetry = 3  ", nil, true},
+		{"mismatch", 3, "const maxRetry = 10", nil, false},
+		{"shifted", 1, "const maxRetry = 3", []string{"const maxRetry = 5"}, true},
+		{"too far", 9, "package main", nil, false},
+		{"original still in place", 3, "func main() {}", []string{"const maxRetry = 3"}, false},
+		{"out of range", 5, "func main() {}\nfoo\nbar", nil, false},
+		{"deletion", 3, "", nil, false},
+		{"same as original", 3, "const maxRetry = 3", []string{"const maxRetry = 3"}, false},
+		{"shifted without original", 1, "const maxRetry = 3", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestionApplied(content, tt.startLine, tt.suggestion, tt.original); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionAppliedNearbyLine(t *testing.T) {
+	// The original line only moved down by one, and the short suggestion
+	// happens to match an unchanged line below it.
+	content := "func f() {\n\tx()\n\tnewline()\n\treturn err\n\t}\n}\n"
+	if suggestionApplied(content, 3, "\t}", []string{"\treturn err"}) {
+		t.Error("a suggestion should not be applied while its original line is still nearby")
+	}
+	// Once the original line is gone, the moved suggestion is found.
+	content = "func f() {\n\tx()\n\tnewline()\n\t}\n}\n"
+	if !suggestionApplied(content, 3, "\t}", []string{"\treturn err"}) {
+		t.Error("expected the moved suggestion to be applied")
+	}
+}
+
+func TestHunkTail(t *testing.T) {
+	hunk := "@@ -1,3 +1,3 @@\n package main\n-const a = 1\n+const a = 2\n \n"
+	got := HunkTail(hunk, 2)
+	want := []string{"const a = 2", ""}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+	if got := HunkTail(hunk, 10); got != nil {
+		t.Errorf("expected nil for too many lines, got %q", got)
+	}
+}
+
+func TestDiffTail(t *testing.T) {
+	hunk := "@@ -1,3 +1,3 @@\r\n package main\r\n-const a = 1\r\n+const a = 2\r\n\\ No newline at end of file\r\n"
+	got := DiffTail(hunk, 2)
+	want := []string{"-const a = 1", "+const a = 2"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+	if got := DiffTail(hunk, 10); len(got) != 3 {
+		t.Errorf("expected the whole hunk, got %q", got)
+	}
+}
+
+type recordingClassifier struct {
+	input *ClassifyInput
+}
+
+func (r *recordingClassifier) ClassifyAll(_ context.Context, input *ClassifyInput) (*ClassifyOutput, error) {
+	r.input = input
+	return &ClassifyOutput{}, nil
+}
+
+func (r *recordingClassifier) Close() {}
+
+func TestAnalyzeAppliedSuggestion(t *testing.T) {
+	line := 3
+	data := &Data{
+		Threads: []Thread{
+			{
+				ID:   "T1",
+				Path: "main.go",
+				Line: &line,
+				Comments: []Comment{
+					{
+						ID:        "C1",
+						Body:      "```suggestion\nconst maxRetry = 3\n```",
+						Author:    "alice",
+						CreatedAt: time.Now(),
+						DiffHunk:  "@@ -1,3 +1,3 @@\n package main\n \n+const maxRetry = 5",
+					},
+				},
+			},
+		},
+		Files: map[string]string{
+			"main.go": "package main\n\nconst maxRetry = 3\n",
+		},
+	}
+
+	rec := &recordingClassifier{}
+	results, err := Analyze(context.Background(), data, rec, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.input != nil {
+		t.Error("classifier should not be called when every thread is resolved by an applied suggestion")
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if !r.Resolved || !r.SuggestionApplied || r.Category != "suggestion" {
+		t.Errorf("expected resolved applied suggestion, got %+v", r)
+	}
+	if r.Suggestion == nil || *r.Suggestion != "const maxRetry = 3" {
+		t.Errorf("unexpected suggestion: %v", r.Suggestion)
+	}
+
+	results, err = Analyze(context.Background(), data, rec, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected 0 unresolved results, got %d", len(results))
+	}
+}
+
+func TestAnalyzePendingSuggestion(t *testing.T) {
+	line := 3
+	data := &Data{
+		Threads: []Thread{
+			{
+				ID:   "T1",
+				Path: "main.go",
+				Line: &line,
+				Comments: []Comment{
+					{ID: "C1", Body: "```suggestion\nconst maxRetry = 3\n```", Author: "alice", CreatedAt: time.Now()},
+				},
+			},
+		},
+		Files: map[string]string{
+			"main.go": "package main\n\nconst maxRetry = 5\n",
+		},
+	}
+
+	rec := &recordingClassifier{}
+	results, err := Analyze(context.Background(), data, rec, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.input == nil || len(rec.input.Threads) != 1 {
+		t.Fatal("expected the thread to be sent to the classifier")
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].SuggestionApplied {
+		t.Error("expected suggestion not to be applied")
+	}
+}
+
+func TestPendingSuggestions(t *testing.T) {
+	start := 2
+	line := 3
+	outdated := Thread{
+		ID:       "T3",
+		Path:     "main.go",
+		Comments: []Comment{{Body: "```suggestion\nfoo\n```"}},
+	}
+	data := &Data{
+		Threads: []Thread{
+			{
+				ID:        "T1",
+				Path:      "main.go",
+				Line:      &line,
+				StartLine: &start,
+				Comments: []Comment{
+					{
+						Body:     "```suggestion\nif err != nil {\n\treturn fmt.Errorf(\"open: %w\", err)\n```",
+						Author:   "alice",
+						URL:      "https://example.com/1",
+						DiffHunk: "@@ -1,3 +1,3 @@\n package main\n+if err != nil {\n+\treturn err",
+					},
+				},
+			},
+			{
+				ID:         "T2",
+				IsResolved: true,
+				Path:       "main.go",
+				Line:       &line,
+				Comments:   []Comment{{Body: "```suggestion\nbar\n```"}},
+			},
+			outdated,
+			{
+				ID:       "T4",
+				Path:     "main.go",
+				Line:     &line,
+				Comments: []Comment{{Body: "No suggestion here"}},
+			},
+		},
+	}
+
+	got := PendingSuggestions(data)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 pending suggestion, got %d", len(got))
+	}
+	s := got[0]
+	if s.ThreadID != "T1" || s.StartLine != 2 || s.EndLine != 3 || s.Author != "alice" {
+		t.Errorf("unexpected suggestion: %+v", s)
+	}
+	if len(s.Original) != 2 || s.Original[0] != "if err != nil {" {
+		t.Errorf("unexpected original lines: %q", s.Original)
+	}
+
+	before := "package main\nif err != nil {\n\treturn err\n}\n"
+	after := "package main\nif err != nil {\n\treturn fmt.Errorf(\"open: %w\", err)\n}\n"
+	changed := "package main\nif err == nil {\n\treturn nil\n}\n"
+	if s.IsAppliedTo(before) || !s.IsAppliedTo(after) {
+		t.Error("IsAppliedTo returned an unexpected result")
+	}
+	if !s.MatchesOriginal(before) || s.MatchesOriginal(changed) {
+		t.Error("MatchesOriginal returned an unexpected result")
+	}
+}
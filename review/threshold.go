@@ -0,0 +1,75 @@
+package review
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Categories are the categories a comment can be classified as.
+var Categories = []string{"suggestion", "nitpick", "issue", "question", "approval", "informational"}
+
+// Thresholds maps a category to the number of unresolved items tolerated.
+// The AnyCategory key applies to every category without its own threshold.
+type Thresholds map[string]int
+
+// AnyCategory is the Thresholds key that matches any category, including
+// "unknown" for items the classifier left out.
+const AnyCategory = "*"
+
+// ParseThresholds parses specs in the form "category" or "category=N", where
+// N is the number of unresolved items of the category tolerated (default 0).
+// Without specs, no unresolved item of any category is tolerated.
+func ParseThresholds(specs []string) (Thresholds, error) {
+	t := Thresholds{}
+	if len(specs) == 0 {
+		t[AnyCategory] = 0
+		return t, nil
+	}
+	for _, spec := range specs {
+		category, n, hasN := strings.Cut(spec, "=")
+		if !slices.Contains(Categories, category) {
+			return nil, fmt.Errorf("unknown category %q (must be one of %s)", category, strings.Join(Categories, ", "))
+		}
+		allowed := 0
+		if hasN {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid threshold %q for %s: must be a non-negative integer", n, category)
+			}
+			allowed = v
+		}
+		t[category] = allowed
+	}
+	return t, nil
+}
+
+// Exceeded returns a label such as "issue (2)" for each category whose
+// unresolved items in results exceed its threshold, in the order of Categories
+// followed by any other categories in alphabetical order.
+func (t Thresholds) Exceeded(results []UnresolvedComment) []string {
+	counts := map[string]int{}
+	var others []string
+	for _, r := range results {
+		if r.Resolved {
+			continue
+		}
+		if counts[r.Category] == 0 && !slices.Contains(Categories, r.Category) {
+			others = append(others, r.Category)
+		}
+		counts[r.Category]++
+	}
+	slices.Sort(others)
+	var exceeded []string
+	for _, c := range slices.Concat(Categories, others) {
+		allowed, ok := t[c]
+		if !ok {
+			allowed, ok = t[AnyCategory]
+		}
+		if ok && counts[c] > allowed {
+			exceeded = append(exceeded, fmt.Sprintf("%s (%d)", c, counts[c]))
+		}
+	}
+	return exceeded
+}
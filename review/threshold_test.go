@@ -0,0 +1,70 @@
+package review
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseThresholds(t *testing.T) {
+	got, err := ParseThresholds([]string{"issue", "nitpick=3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["issue"] != 0 || got["nitpick"] != 3 {
+		t.Errorf("unexpected thresholds: %v", got)
+	}
+
+	all, err := ParseThresholds(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[AnyCategory] != 0 {
+		t.Errorf("expected any category, got %v", all)
+	}
+
+	for _, spec := range []string{"bug", "issue=-1", "issue=x"} {
+		if _, err := ParseThresholds([]string{spec}); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestThresholdsExceeded(t *testing.T) {
+	results := []UnresolvedComment{
+		{Category: "issue"},
+		{Category: "issue"},
+		{Category: "issue", Resolved: true},
+		{Category: "nitpick"},
+		{Category: "nitpick"},
+		{Category: "question"},
+	}
+	th := Thresholds{"issue": 1, "nitpick": 2, "suggestion": 0}
+	got := th.Exceeded(results)
+	want := []string{"issue (2)"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestThresholdsExceededAnyCategory(t *testing.T) {
+	results := []UnresolvedComment{
+		{Category: "unknown"},
+		{Category: "issue"},
+		{Category: "approval", Resolved: true},
+	}
+	all, err := ParseThresholds(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := all.Exceeded(results)
+	want := []string{"issue (1)", "unknown (1)"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Only the listed categories are checked with --fail-on.
+	th := Thresholds{"issue": 1}
+	if got := th.Exceeded(results); len(got) != 0 {
+		t.Errorf("expected nothing exceeded, got %v", got)
+	}
+}